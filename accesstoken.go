@@ -0,0 +1,169 @@
+package bitgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Scope is a permission granted to an access token.
+type Scope string
+
+// Scopes of access tokens.
+const (
+	// ScopeOpenID allows to identify the user.
+	ScopeOpenID Scope = "openid"
+	// ScopeProfile allows to view the user profile.
+	ScopeProfile Scope = "profile"
+	// ScopeWalletCreate allows to create wallets.
+	ScopeWalletCreate Scope = "wallet_create"
+	// ScopeWalletViewAll allows to view all wallets of the user.
+	ScopeWalletViewAll Scope = "wallet_view_all"
+	// ScopeWalletSpendAll allows to spend from all wallets of the user.
+	ScopeWalletSpendAll Scope = "wallet_spend_all"
+	// ScopeWalletManageAll allows to manage all wallets of the user, e.g., their users and policies.
+	ScopeWalletManageAll Scope = "wallet_manage_all"
+	// ScopeWalletApproveAll allows to approve pending approvals of all wallets of the user.
+	ScopeWalletApproveAll Scope = "wallet_approve_all"
+	// ScopeWalletEditAll allows to edit all wallets of the user, e.g., their labels.
+	ScopeWalletEditAll Scope = "wallet_edit_all"
+	// ScopeEnterpriseViewAll allows to view enterprises of the user.
+	ScopeEnterpriseViewAll Scope = "enterprise_view_all"
+	// ScopeEnterpriseManageAll allows to manage enterprises of the user.
+	ScopeEnterpriseManageAll Scope = "enterprise_manage_all"
+)
+
+// AccessToken is a long-lived access token of a user.
+// For more details, see https://bitgo.github.io/bitgo-docs/#access-tokens.
+type AccessToken struct {
+	// The ID of the token which is used to revoke it.
+	ID string `json:"id"`
+	// The token itself. It's only returned when the token is created.
+	Token string `json:"token"`
+	// The label of the token.
+	Label string `json:"label"`
+	// The ID of the user the token belongs to.
+	User string `json:"user"`
+	// The client the token was created by, e.g., "bitgo".
+	Client string `json:"client"`
+	// Scopes granted to the token.
+	Scope []Scope `json:"scope"`
+	// The time the token was created.
+	Created time.Time `json:"created"`
+	// The time the token expires.
+	Expires time.Time `json:"expires"`
+	// The origin the token was created from.
+	Origin string `json:"origin"`
+	// Whether the token can be extended beyond its initial duration.
+	IsExtensible bool `json:"isExtensible"`
+	// IP addresses or CIDR ranges the token can be used from.
+	IPRestrict []string `json:"ipRestrict"`
+	// Max amount in satoshis the token can spend.
+	TxValueLimit Amount `json:"txValueLimit"`
+}
+
+// AccessTokenParams represents parameters used when creating an access token.
+// For more details, see https://bitgo.github.io/bitgo-docs/#create-access-token.
+type AccessTokenParams struct {
+	// The label of the token to tell it apart from other tokens.
+	Label string
+	// One-time password of the user.
+	OTP string
+	// How long the token is valid, it's rounded down to seconds.
+	// Zero means the API default is used (10 years).
+	Duration time.Duration
+	// Scopes granted to the token.
+	Scope []Scope
+	// IP addresses or CIDR ranges the token can be used from. Empty means any address.
+	IPRestrict []string
+	// Max amount in satoshis the token can spend. Zero means the token can't spend.
+	TxValueLimit Amount
+}
+
+// Validate checks whether the params are accepted by the API.
+func (p *AccessTokenParams) Validate() error {
+	switch {
+	case p == nil:
+		return errors.New("bitgo: access token params must not be nil")
+	case p.Label == "":
+		return errors.New("bitgo: access token label must not be empty")
+	case len(p.Scope) == 0:
+		return errors.New("bitgo: access token scope must not be empty")
+	case p.Duration < 0:
+		return fmt.Errorf("bitgo: access token duration must not be negative, got %s", p.Duration)
+	case p.TxValueLimit < 0:
+		return fmt.Errorf("bitgo: access token tx value limit must not be negative, got %d", p.TxValueLimit)
+	}
+	for _, ip := range p.IPRestrict {
+		if net.ParseIP(ip) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			return fmt.Errorf("bitgo: access token ip restriction %q is neither IP address nor CIDR", ip)
+		}
+	}
+	return nil
+}
+
+// CreateAccessToken creates a long-lived access token of the user.
+// The token can be read only once from the result's Token field.
+func (s *userService) CreateAccessToken(ctx context.Context, params *AccessTokenParams) (*AccessToken, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	body := struct {
+		Label        string   `json:"label"`
+		OTP          string   `json:"otp,omitempty"`
+		Duration     int      `json:"duration,omitempty"`
+		Scope        []Scope  `json:"scope"`
+		IPRestrict   []string `json:"ipRestrict,omitempty"`
+		TxValueLimit Amount   `json:"txValueLimit,omitempty"`
+	}{
+		Label:        params.Label,
+		OTP:          params.OTP,
+		Duration:     int(params.Duration / time.Second),
+		Scope:        params.Scope,
+		IPRestrict:   params.IPRestrict,
+		TxValueLimit: params.TxValueLimit,
+	}
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "user/accesstoken", nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var t AccessToken
+	if _, err = s.client.Do(req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// AccessTokens returns access tokens of the user. Their Token fields are empty.
+func (s *userService) AccessTokens(ctx context.Context) ([]AccessToken, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "user/accesstoken", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		AccessTokens []AccessToken `json:"accessTokens"`
+	}
+	if _, err = s.client.Do(req, &v); err != nil {
+		return nil, err
+	}
+	return v.AccessTokens, nil
+}
+
+// RevokeAccessToken revokes the access token by its ID.
+func (s *userService) RevokeAccessToken(ctx context.Context, tokenID string) error {
+	path := fmt.Sprintf("user/accesstoken/%s", tokenID)
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Do(req, nil)
+	return err
+}
@@ -0,0 +1,124 @@
+package bitgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestCreateAccessToken(t *testing.T) {
+	token, err := ioutil.ReadFile(filepath.Join("testdata", "accesstoken.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/user/accesstoken" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write(token)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	got, err := client.User.CreateAccessToken(context.Background(), &bitgo.AccessTokenParams{
+		Label:        "payouts",
+		OTP:          "0000000",
+		Duration:     90 * 24 * time.Hour,
+		Scope:        []bitgo.Scope{bitgo.ScopeWalletViewAll, bitgo.ScopeWalletSpendAll},
+		IPRestrict:   []string{"203.0.113.7", "198.51.100.0/24"},
+		TxValueLimit: 100000000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantBody := map[string]interface{}{
+		"label":        "payouts",
+		"otp":          "0000000",
+		"duration":     float64(7776000),
+		"scope":        []interface{}{"wallet_view_all", "wallet_spend_all"},
+		"ipRestrict":   []interface{}{"203.0.113.7", "198.51.100.0/24"},
+		"txValueLimit": float64(100000000),
+	}
+	if !reflect.DeepEqual(body, wantBody) {
+		t.Errorf("expected %v body, got %v", wantBody, body)
+	}
+
+	want := bitgo.AccessToken{
+		ID:           "5a1e3b8f6d2c4f0007a1b2c3",
+		Token:        "v2x5f0c3b8e7a2d4c1f9e6b3a8d5c2f7e4b1a9d6c3f0e7b4a1d8c5f2e9b6a3d0c7f4",
+		Label:        "payouts",
+		User:         "55e8a1a5df8380e0e30e20c4",
+		Client:       "bitgo",
+		Scope:        []bitgo.Scope{bitgo.ScopeWalletViewAll, bitgo.ScopeWalletSpendAll},
+		Created:      time.Date(2017, 11, 29, 12, 0, 0, 0, time.UTC),
+		Expires:      time.Date(2027, 11, 27, 12, 0, 0, 0, time.UTC),
+		Origin:       "test.bitgo.com",
+		IPRestrict:   []string{"203.0.113.7", "198.51.100.0/24"},
+		TxValueLimit: 100000000,
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("should be %#v, not %#v", want, *got)
+	}
+}
+
+func TestCreateAccessTokenValidate(t *testing.T) {
+	client := bitgo.NewClient(bitgo.WithBaseURL("http://0.0.0.0:1"))
+	scope := []bitgo.Scope{bitgo.ScopeWalletViewAll}
+	for _, p := range []*bitgo.AccessTokenParams{
+		{Scope: scope},
+		{Label: "payouts"},
+		{Label: "payouts", Scope: scope, Duration: -time.Second},
+		{Label: "payouts", Scope: scope, TxValueLimit: -1},
+		{Label: "payouts", Scope: scope, IPRestrict: []string{"localhost"}},
+		nil,
+	} {
+		if _, err := client.User.CreateAccessToken(context.Background(), p); err == nil {
+			t.Errorf("expected validation error for %+v", p)
+		}
+	}
+}
+
+func TestAccessTokens(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"accessTokens":[{"id":"a1","label":"payouts","scope":["wallet_view_all"]}]}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+	tokens, err := client.User.AccessTokens(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 1 || tokens[0].ID != "a1" || tokens[0].Label != "payouts" || tokens[0].Token != "" {
+		t.Errorf("unexpected access tokens %+v", tokens)
+	}
+	if err = client.User.RevokeAccessToken(ctx, tokens[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /api/v1/user/accesstoken",
+		"DELETE /api/v1/user/accesstoken/a1",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected %v, got %v", want, requests)
+	}
+}
@@ -0,0 +1,153 @@
+package bitgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Chain is a BIP32 chain of wallet addresses which defines the address script type.
+// Even chains are used for receive addresses, odd chains are used for change.
+type Chain int
+
+// Chains of wallet addresses.
+const (
+	// ChainP2SH is a chain of legacy P2SH receive addresses.
+	ChainP2SH Chain = 0
+	// ChainP2SHChange is a chain of legacy P2SH change addresses.
+	ChainP2SHChange Chain = 1
+	// ChainP2SHP2WSH is a chain of SegWit receive addresses wrapped in P2SH.
+	ChainP2SHP2WSH Chain = 10
+	// ChainP2SHP2WSHChange is a chain of SegWit change addresses wrapped in P2SH.
+	ChainP2SHP2WSHChange Chain = 11
+	// ChainP2WSH is a chain of native SegWit (bech32) receive addresses.
+	ChainP2WSH Chain = 20
+	// ChainP2WSHChange is a chain of native SegWit (bech32) change addresses.
+	ChainP2WSHChange Chain = 21
+)
+
+// IsChange returns true if the chain is used for change addresses.
+func (c Chain) IsChange() bool {
+	return c%2 == 1
+}
+
+// IsSegwit returns true if addresses of the chain are SegWit addresses.
+func (c Chain) IsSegwit() bool {
+	return c >= ChainP2SHP2WSH
+}
+
+// Address is a wallet address.
+// For more details, see https://bitgo.github.io/bitgo-docs/#wallet-addresses.
+type Address struct {
+	// The address string, e.g., "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4".
+	Address string `json:"address"`
+	// The chain of the address.
+	Chain Chain `json:"chain"`
+	// The index of the address on the chain.
+	Index int `json:"index"`
+	// The BIP32 path of the address relative to the wallet, e.g., "/0/13".
+	Path string `json:"path"`
+	// The ID of the wallet the address belongs to.
+	Wallet string `json:"wallet"`
+	// The redeem script of the address (in hex format).
+	RedeemScript string `json:"redeemScript"`
+	// The witness script of SegWit addresses (in hex format).
+	WitnessScript string `json:"witnessScript"`
+	// The balance of the address in satoshis.
+	Balance Amount `json:"balance"`
+	// Total amount in satoshis received by the address.
+	Received Amount `json:"received"`
+	// Total amount in satoshis sent from the address.
+	Sent Amount `json:"sent"`
+	// Number of transactions involving the address.
+	TxCount int `json:"txCount"`
+}
+
+// AddressList is a list of addresses as retrieved from a list endpoint.
+type AddressList struct {
+	ListMeta
+	Addresses []Address `json:"addresses"`
+}
+
+// AddressesParams represents query parameters used when listing addresses.
+// For more details, see https://bitgo.github.io/bitgo-docs/#list-wallet-addresses.
+type AddressesParams struct {
+	// Only include addresses of the chain. Nil means addresses of all chains are listed.
+	Chain *Chain
+	// Max number of results to return in a single call (defaults to 100, max is 500).
+	Limit int
+	// The starting index number to list from (defaults to 0).
+	Skip int
+}
+
+// values returns the params encoded as a query string, zero values are omitted.
+func (p *AddressesParams) values() url.Values {
+	v := url.Values{}
+	if p.Chain != nil {
+		v.Set("chain", strconv.Itoa(int(*p.Chain)))
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Skip > 0 {
+		v.Set("skip", strconv.Itoa(p.Skip))
+	}
+	return v
+}
+
+// CreateAddress creates a new address on the chain of the wallet.
+func (s *walletService) CreateAddress(ctx context.Context, walletID string, chain Chain) (*Address, error) {
+	path := fmt.Sprintf("wallet/%s/address/%d", walletID, chain)
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var a Address
+	if _, err = s.client.Do(req, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Addresses returns a Pager to iterate over addresses of the wallet.
+// Nil params means API defaults are used. Pagination starts from params.Skip.
+func (s *walletService) Addresses(walletID string, params *AddressesParams) *Pager[Address] {
+	path := fmt.Sprintf("wallet/%s/addresses", walletID)
+	var p AddressesParams
+	if params != nil {
+		p = *params
+	}
+
+	return NewPager(p.Skip, func(ctx context.Context, skip int) (*Page[Address], error) {
+		q := p
+		q.Skip = skip
+		req, err := s.client.NewRequest(ctx, http.MethodGet, path, q.values(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		v := AddressList{}
+		if _, err = s.client.Do(req, &v); err != nil {
+			return nil, err
+		}
+		return &Page[Address]{ListMeta: v.ListMeta, Items: v.Addresses}, nil
+	})
+}
+
+// Address gets the wallet address with its balance and totals.
+func (s *walletService) Address(ctx context.Context, walletID, address string) (*Address, error) {
+	path := fmt.Sprintf("wallet/%s/addresses/%s", walletID, address)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var a Address
+	if _, err = s.client.Do(req, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,121 @@
+package bitgo_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestAddress(t *testing.T) {
+	filename := filepath.Join("testdata", "address.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	want := bitgo.Address{
+		Address:       "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4",
+		Chain:         bitgo.ChainP2SHP2WSH,
+		Index:         13,
+		Path:          "/10/13",
+		Wallet:        "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr",
+		RedeemScript:  "0020e2ad4f9a0b6dcb1a2ea38a1d6d63ef6e7e3aeb97a8d4d7b1da7bdc8e2c5bb1c4",
+		WitnessScript: "5221031b2a9bd1b5d0d62a4e8c3e7f0b9b9d3fd1c0b8ed5c27c1d8c3a7b3a1b5f9a3e021027d1bce14b2f8d07b2d4fb35a8c98f4f28b3a1e7d5e7c3f1f1a3b2d8e9d0f7c3a52ae",
+		Balance:       150000,
+		Received:      250000,
+		Sent:          100000,
+		TxCount:       3,
+	}
+
+	created, err := client.Wallet.CreateAddress(context.Background(), want.Wallet, bitgo.ChainP2SHP2WSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.Wallet.Address(context.Background(), want.Wallet, want.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []*bitgo.Address{created, got} {
+		if !reflect.DeepEqual(*a, want) {
+			t.Errorf("should be %#v, not %#v", want, *a)
+		}
+	}
+
+	wantRequests := []string{
+		"POST /api/v1/wallet/2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr/address/10",
+		"GET /api/v1/wallet/2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr/addresses/2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected requests %q, got %q", wantRequests, requests)
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		skip := r.URL.Query().Get("skip")
+		if skip == "" {
+			skip = "0"
+		}
+		fmt.Fprintf(w, `{"count":1,"start":%s,"total":2,"addresses":[{"address":"a%s","chain":1}]}`, skip, skip)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	chain := bitgo.ChainP2SHChange
+	var got []string
+	for a, err := range client.Wallet.Addresses("wallet", &bitgo.AddressesParams{Chain: &chain, Limit: 1}).All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a.Chain.IsChange() {
+			t.Errorf("expected change address, got chain %d", a.Chain)
+		}
+		got = append(got, a.Address)
+	}
+	if want := []string{"a0", "a1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	wantQueries := []string{"chain=1&limit=1", "chain=1&limit=1&skip=1"}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("expected queries %q, got %q", wantQueries, queries)
+	}
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		chain          bitgo.Chain
+		change, segwit bool
+	}{
+		{bitgo.ChainP2SH, false, false},
+		{bitgo.ChainP2SHChange, true, false},
+		{bitgo.ChainP2SHP2WSH, false, true},
+		{bitgo.ChainP2SHP2WSHChange, true, true},
+		{bitgo.ChainP2WSH, false, true},
+		{bitgo.ChainP2WSHChange, true, true},
+	}
+	for _, test := range tests {
+		if test.chain.IsChange() != test.change || test.chain.IsSegwit() != test.segwit {
+			t.Errorf("chain %d: expected change %v and segwit %v", test.chain, test.change, test.segwit)
+		}
+	}
+}
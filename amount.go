@@ -0,0 +1,215 @@
+package bitgo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount is an amount of bitcoins stored as an integer number of satoshis,
+// so there are no rounding errors as with floating point numbers.
+// It's encoded in JSON as a number of satoshis.
+// Amount implements flag.Value, so it can be parsed from command line flags, e.g., -min-value="290 mBTC".
+type Amount int64
+
+// Unit is a denomination of bitcoin amounts.
+// Its value is a number of decimal places relative to satoshis.
+type Unit int
+
+// Supported units of bitcoin amounts.
+const (
+	// UnitSatoshi is the smallest unit of bitcoin, "sat".
+	UnitSatoshi Unit = 0
+	// UnitBits is one millionth of a bitcoin (100 satoshis), "bits".
+	UnitBits Unit = 2
+	// UnitMilliBTC is one thousandth of a bitcoin (100000 satoshis), "mBTC".
+	UnitMilliBTC Unit = 5
+	// UnitBTC is one bitcoin (100000000 satoshis), "BTC".
+	UnitBTC Unit = 8
+)
+
+var (
+	// ErrAmountOverflow is returned when an amount doesn't fit into int64 satoshis.
+	ErrAmountOverflow = errors.New("bitgo: amount overflow")
+	// ErrAmountSyntax is returned when an amount can't be parsed.
+	ErrAmountSyntax = errors.New("bitgo: invalid amount syntax")
+)
+
+// String returns the unit's symbol.
+func (u Unit) String() string {
+	switch u {
+	case UnitSatoshi:
+		return "sat"
+	case UnitBits:
+		return "bits"
+	case UnitMilliBTC:
+		return "mBTC"
+	case UnitBTC:
+		return "BTC"
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+// parseUnit returns a unit by its symbol.
+func parseUnit(s string) (Unit, bool) {
+	switch strings.ToLower(s) {
+	case "sat", "sats", "satoshi", "satoshis":
+		return UnitSatoshi, true
+	case "bit", "bits", "ubtc", "μbtc":
+		return UnitBits, true
+	case "mbtc":
+		return UnitMilliBTC, true
+	case "btc":
+		return UnitBTC, true
+	}
+	return 0, false
+}
+
+// ParseAmount parses an exact decimal amount with an optional unit, e.g.,
+// "0.29", "0.29 BTC", "290 mBTC", "290000 bits" or "1500 sat".
+// Amounts without a unit are in bitcoins.
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	num := strings.TrimRightFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	unit := UnitBTC
+	if symbol := strings.TrimSpace(s[len(num):]); symbol != "" {
+		var ok bool
+		if unit, ok = parseUnit(symbol); !ok {
+			return 0, fmt.Errorf("%w: unknown unit %q", ErrAmountSyntax, symbol)
+		}
+	}
+
+	neg := strings.HasPrefix(num, "-")
+	if neg || strings.HasPrefix(num, "+") {
+		num = num[1:]
+	}
+	intPart, fracPart := num, ""
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		intPart, fracPart = num[:i], num[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("%w: %q", ErrAmountSyntax, s)
+	}
+	if len(fracPart) > int(unit) {
+		return 0, fmt.Errorf("%w: %q has more than %d decimal places", ErrAmountSyntax, s, int(unit))
+	}
+
+	// Amount is parsed as satoshis, e.g., "0.29 BTC" becomes "029000000".
+	digits := intPart + fracPart + strings.Repeat("0", int(unit)-len(fracPart))
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return 0, nil
+	}
+	sat, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, ErrAmountOverflow
+	}
+	if neg {
+		sat = -sat
+	}
+	return Amount(sat), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Format formats the amount in the unit without a unit symbol,
+// e.g., Amount(117).Format(UnitBTC) returns "0.00000117".
+func (a Amount) Format(u Unit) string {
+	sat := strconv.FormatInt(int64(a), 10)
+	neg := a < 0
+	if neg {
+		sat = sat[1:]
+	}
+	if u > 0 {
+		if len(sat) <= int(u) {
+			sat = strings.Repeat("0", int(u)-len(sat)+1) + sat
+		}
+		sat = sat[:len(sat)-int(u)] + "." + sat[len(sat)-int(u):]
+	}
+	if neg {
+		return "-" + sat
+	}
+	return sat
+}
+
+// String returns the amount in bitcoins, e.g., "0.29000000 BTC".
+func (a Amount) String() string {
+	return a.Format(UnitBTC) + " " + UnitBTC.String()
+}
+
+// BTC returns the amount in bitcoins as a floating point number which is handy for display.
+func (a Amount) BTC() float64 {
+	return float64(a) * Satoshi
+}
+
+// Add returns a sum of the amounts or ErrAmountOverflow.
+func (a Amount) Add(b Amount) (Amount, error) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
+		return 0, ErrAmountOverflow
+	}
+	return c, nil
+}
+
+// Sub returns a difference of the amounts or ErrAmountOverflow.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	c := a - b
+	if (b > 0 && c > a) || (b < 0 && c < a) {
+		return 0, ErrAmountOverflow
+	}
+	return c, nil
+}
+
+// Mul returns the amount multiplied by n or ErrAmountOverflow.
+func (a Amount) Mul(n int64) (Amount, error) {
+	if a == 0 || n == 0 {
+		return 0, nil
+	}
+	c := int64(a) * n
+	if c/n != int64(a) || (int64(a) == math.MinInt64 && n == -1) {
+		return 0, ErrAmountOverflow
+	}
+	return Amount(c), nil
+}
+
+// Set parses the amount from a command line flag, see ParseAmount.
+func (a *Amount) Set(s string) error {
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalJSON encodes the amount as a number of satoshis.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(a), 10), nil
+}
+
+// UnmarshalJSON decodes the amount from a number of satoshis.
+// The number can be quoted as some API endpoints return large amounts as strings.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	sat, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("bitgo: amount must be an integer number of satoshis: %s", b)
+	}
+	*a = Amount(sat)
+	return nil
+}
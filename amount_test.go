@@ -0,0 +1,149 @@
+package bitgo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"math"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bitgo.Amount
+	}{
+		{"0.29", 29000000},
+		{"0.29 BTC", 29000000},
+		{"0.29BTC", 29000000},
+		{"290 mBTC", 29000000},
+		{"290000 bits", 29000000},
+		{"1500 sat", 1500},
+		{"0.00000001", 1},
+		{".5", 50000000},
+		{"-1 sat", -1},
+		{"0", 0},
+		{"21000000", 2100000000000000},
+	}
+	for _, test := range tests {
+		got, err := bitgo.ParseAmount(test.in)
+		if err != nil {
+			t.Errorf("ParseAmount(%q) failed: %v", test.in, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseAmount(%q) = %d, want %d", test.in, got, test.want)
+		}
+	}
+}
+
+func TestParseAmountError(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"", bitgo.ErrAmountSyntax},
+		{"BTC", bitgo.ErrAmountSyntax},
+		{"1e5", bitgo.ErrAmountSyntax},
+		{"0.000000001", bitgo.ErrAmountSyntax},
+		{"1.5 sat", bitgo.ErrAmountSyntax},
+		{"1 ETH", bitgo.ErrAmountSyntax},
+		{"-+5", bitgo.ErrAmountSyntax},
+		{"+-5", bitgo.ErrAmountSyntax},
+		{"--5", bitgo.ErrAmountSyntax},
+		{"100000000000 BTC", bitgo.ErrAmountOverflow},
+	}
+	for _, test := range tests {
+		_, err := bitgo.ParseAmount(test.in)
+		if !errors.Is(err, test.want) {
+			t.Errorf("ParseAmount(%q) error = %v, want %v", test.in, err, test.want)
+		}
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	tests := []struct {
+		amount bitgo.Amount
+		unit   bitgo.Unit
+		want   string
+	}{
+		{29000000, bitgo.UnitBTC, "0.29000000"},
+		{117, bitgo.UnitBTC, "0.00000117"},
+		{-117, bitgo.UnitBTC, "-0.00000117"},
+		{29000000, bitgo.UnitMilliBTC, "290.00000"},
+		{29000000, bitgo.UnitBits, "290000.00"},
+		{1500, bitgo.UnitSatoshi, "1500"},
+	}
+	for _, test := range tests {
+		got := test.amount.Format(test.unit)
+		if got != test.want {
+			t.Errorf("Amount(%d).Format(%s) = %q, want %q", test.amount, test.unit, got, test.want)
+		}
+	}
+
+	if got := bitgo.Amount(29000000).String(); got != "0.29000000 BTC" {
+		t.Errorf("expected 0.29000000 BTC, got %q", got)
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a := bitgo.Amount(math.MaxInt64)
+	if _, err := a.Add(1); err != bitgo.ErrAmountOverflow {
+		t.Errorf("expected overflow, got %v", err)
+	}
+	if _, err := bitgo.Amount(math.MinInt64).Sub(1); err != bitgo.ErrAmountOverflow {
+		t.Errorf("expected overflow, got %v", err)
+	}
+	if _, err := a.Mul(2); err != bitgo.ErrAmountOverflow {
+		t.Errorf("expected overflow, got %v", err)
+	}
+
+	b, err := bitgo.Amount(1500).Add(500)
+	if err != nil || b != 2000 {
+		t.Errorf("1500 + 500 = %d, %v", b, err)
+	}
+	if b, err = b.Sub(2500); err != nil || b != -500 {
+		t.Errorf("2000 - 2500 = %d, %v", b, err)
+	}
+	if b, err = b.Mul(-3); err != nil || b != 1500 {
+		t.Errorf("-500 * -3 = %d, %v", b, err)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		Value  bitgo.Amount `json:"value"`
+		Quoted bitgo.Amount `json:"quoted"`
+	}
+	if err := json.Unmarshal([]byte(`{"value":78273186932,"quoted":"1488"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != 78273186932 || v.Quoted != 1488 {
+		t.Fatalf("unexpected amounts %+v", v)
+	}
+	if err := json.Unmarshal([]byte(`{"value":0.29}`), &v); err == nil {
+		t.Error("expected error for fractional satoshis")
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"value":78273186932,"quoted":1488}`; string(b) != want {
+		t.Errorf("expected %s, got %s", want, b)
+	}
+}
+
+func TestAmountFlag(t *testing.T) {
+	var a bitgo.Amount
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&a, "max-value", "")
+	if err := fs.Parse([]string{"-max-value=290 mBTC"}); err != nil {
+		t.Fatal(err)
+	}
+	if a != 29000000 {
+		t.Errorf("expected 29000000 satoshis, got %d", a)
+	}
+}
@@ -0,0 +1,296 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// States of pending approvals.
+const (
+	// ApprovalStatePending is a state of an approval which awaits a decision of wallet admins.
+	ApprovalStatePending = "pending"
+	// ApprovalStateApproved is a state of an approved approval, e.g., its transaction was sent.
+	ApprovalStateApproved = "approved"
+	// ApprovalStateRejected is a state of a rejected approval.
+	ApprovalStateRejected = "rejected"
+)
+
+// ApprovalTypeTransactionRequest is a type of an approval of a transaction.
+const ApprovalTypeTransactionRequest = "transactionRequest"
+
+// defaultApprovalPollInterval is how often WaitForApproval checks an approval.
+const defaultApprovalPollInterval = 10 * time.Second
+
+// PendingApproval is a wallet action such as a transaction or a policy change
+// which requires approval of other wallet admins.
+// For more details, see https://bitgo.github.io/bitgo-docs/#pending-approvals.
+type PendingApproval struct {
+	// The ID of the pending approval.
+	ID string `json:"id"`
+	// The ID of the wallet the approval belongs to.
+	WalletID string `json:"walletId"`
+	// The ID of the enterprise the approval belongs to.
+	Enterprise string `json:"enterprise"`
+	// The ID of the user who created the approval.
+	Creator string `json:"creator"`
+	// The time the approval was created.
+	CreateDate time.Time `json:"createDate"`
+	// The state of the approval, e.g., "pending", "approved" or "rejected".
+	State string `json:"state"`
+	// The number of approvals required to resolve the approval.
+	ApprovalsRequired int `json:"approvalsRequired"`
+	// The details of the action being approved.
+	Info ApprovalInfo `json:"info"`
+}
+
+// Resolved returns true if the approval was approved or rejected.
+func (a *PendingApproval) Resolved() bool {
+	return a.State == ApprovalStateApproved || a.State == ApprovalStateRejected
+}
+
+// ApprovalInfo describes the action being approved.
+type ApprovalInfo struct {
+	// The type of the action, e.g., "transactionRequest" or "policyRuleRequest".
+	Type string `json:"type"`
+	// The transaction being approved if the type is "transactionRequest".
+	TransactionRequest *TransactionRequest `json:"transactionRequest,omitempty"`
+	// Raw is the info JSON object as returned by the API.
+	// It gives access to details of other action types.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the approval info and keeps its raw JSON.
+func (i *ApprovalInfo) UnmarshalJSON(b []byte) error {
+	type approvalInfo ApprovalInfo
+	if err := json.Unmarshal(b, (*approvalInfo)(i)); err != nil {
+		return err
+	}
+	i.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// TransactionRequest is a transaction which requires approval.
+type TransactionRequest struct {
+	// The total amount in satoshis sent to the recipients.
+	RequestedAmount Amount `json:"requestedAmount"`
+	// Transaction fee in satoshis.
+	Fee Amount `json:"fee"`
+	// The ID of the wallet which sends the transaction.
+	SourceWallet string `json:"sourceWallet"`
+	// The destination addresses and amounts.
+	Recipients []Recipient `json:"recipients"`
+	// A note attached to the transaction which is visible to wallet users.
+	Message string `json:"message"`
+	// A unique ID of the transaction which was set when it was sent.
+	SequenceID string `json:"sequenceId"`
+	// The ID of the policy rule which requires approval.
+	TriggeredPolicy string `json:"triggeredPolicy"`
+	// The serialized transaction (in hex format) which awaits approval.
+	Transaction string `json:"transaction,omitempty"`
+}
+
+// PendingApproval decodes the pending approval from the body of 202 Accepted response.
+// The body either has the approval object or its ID in "pendingApproval" field,
+// in the latter case only ID and State are known and the approval can be fetched with PendingApprovals.Get.
+// It returns false if the error doesn't indicate that approval is required.
+func (e Error) PendingApproval() (*PendingApproval, bool) {
+	if !e.IsApprovalRequired() {
+		return nil, false
+	}
+	var v struct {
+		ID              string          `json:"id"`
+		PendingApproval json.RawMessage `json:"pendingApproval"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &v); err != nil {
+		return nil, false
+	}
+
+	var a PendingApproval
+	switch {
+	case len(v.PendingApproval) > 0 && v.PendingApproval[0] == '"':
+		if err := json.Unmarshal(v.PendingApproval, &a.ID); err != nil {
+			return nil, false
+		}
+		a.State = ApprovalStatePending
+	case len(v.PendingApproval) > 0 && v.PendingApproval[0] == '{':
+		if err := json.Unmarshal(v.PendingApproval, &a); err != nil {
+			return nil, false
+		}
+	case v.ID != "":
+		if err := json.Unmarshal([]byte(e.Body), &a); err != nil {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	return &a, true
+}
+
+// PendingApprovalService communicates with pending approvals API endpoints.
+// See bitgomock package for its mock implementation.
+type PendingApprovalService interface {
+	// List returns pending approvals of a wallet or an enterprise.
+	List(ctx context.Context, params *PendingApprovalsParams) ([]PendingApproval, error)
+	// Get gets the pending approval by its ID.
+	Get(ctx context.Context, approvalID string) (*PendingApproval, error)
+	// Approve approves the pending approval, see ApproveParams.
+	Approve(ctx context.Context, approvalID string, params *ApproveParams) (*PendingApproval, error)
+	// Reject rejects the pending approval, the OTP is required by BitGo.
+	Reject(ctx context.Context, approvalID, otp string) (*PendingApproval, error)
+	// WaitForApproval polls the pending approval until it's approved or rejected.
+	WaitForApproval(ctx context.Context, approvalID string) (*PendingApproval, error)
+}
+
+// pendingApprovalService communicates with pending approvals API endpoints.
+type pendingApprovalService struct {
+	client *Client
+}
+
+var _ PendingApprovalService = (*pendingApprovalService)(nil)
+
+// PendingApprovalsParams represents query parameters used when listing pending approvals.
+// Either WalletID or Enterprise must be set.
+// For more details, see https://bitgo.github.io/bitgo-docs/#list-pending-approvals.
+type PendingApprovalsParams struct {
+	// The ID of the wallet whose approvals are listed.
+	WalletID string
+	// The ID of the enterprise whose approvals are listed.
+	Enterprise string
+}
+
+// Validate checks whether the params are accepted by the API.
+func (p *PendingApprovalsParams) Validate() error {
+	if (p.WalletID == "") == (p.Enterprise == "") {
+		return errors.New("bitgo: pending approvals need either wallet ID or enterprise")
+	}
+	return nil
+}
+
+// values returns the params encoded as a query string, zero values are omitted.
+func (p *PendingApprovalsParams) values() url.Values {
+	v := url.Values{}
+	if p.WalletID != "" {
+		v.Set("walletId", p.WalletID)
+	}
+	if p.Enterprise != "" {
+		v.Set("enterprise", p.Enterprise)
+	}
+	return v
+}
+
+// List returns pending approvals of a wallet or an enterprise.
+func (s *pendingApprovalService) List(ctx context.Context, params *PendingApprovalsParams) ([]PendingApproval, error) {
+	if params == nil {
+		params = &PendingApprovalsParams{}
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "pendingapprovals", params.values(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		PendingApprovals []PendingApproval `json:"pendingApprovals"`
+	}
+	if _, err = s.client.Do(req, &v); err != nil {
+		return nil, err
+	}
+	return v.PendingApprovals, nil
+}
+
+// Get gets the pending approval by its ID.
+func (s *pendingApprovalService) Get(ctx context.Context, approvalID string) (*PendingApproval, error) {
+	path := fmt.Sprintf("pendingapprovals/%s", approvalID)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var a PendingApproval
+	if _, err = s.client.Do(req, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ApproveParams represents parameters used when approving a pending approval.
+type ApproveParams struct {
+	// One-time password of the user, it's required by BitGo.
+	OTP string `json:"otp,omitempty"`
+	// The serialized half-signed transaction (in hex format).
+	// BitGo requires it to approve a "transactionRequest" approval,
+	// the transaction is signed by the approver with SignTransaction.
+	// Other types of approvals don't need it.
+	Tx string `json:"tx,omitempty"`
+}
+
+// Approve approves the pending approval, see ApproveParams.
+// An approved transaction is sent by BitGo.
+func (s *pendingApprovalService) Approve(ctx context.Context, approvalID string, params *ApproveParams) (*PendingApproval, error) {
+	if params == nil {
+		params = &ApproveParams{}
+	}
+	return s.update(ctx, approvalID, ApprovalStateApproved, *params)
+}
+
+// Reject rejects the pending approval, the OTP is required by BitGo.
+func (s *pendingApprovalService) Reject(ctx context.Context, approvalID, otp string) (*PendingApproval, error) {
+	return s.update(ctx, approvalID, ApprovalStateRejected, ApproveParams{OTP: otp})
+}
+
+// update changes the state of the pending approval.
+func (s *pendingApprovalService) update(ctx context.Context, approvalID, state string, params ApproveParams) (*PendingApproval, error) {
+	path := fmt.Sprintf("pendingapprovals/%s", approvalID)
+	body := struct {
+		State string `json:"state"`
+		ApproveParams
+	}{
+		State:         state,
+		ApproveParams: params,
+	}
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var a PendingApproval
+	if _, err = s.client.Do(req, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// WaitForApproval polls the pending approval until it's approved or rejected and returns it,
+// so the caller should check its State. Polling stops when ctx is done.
+// The poll interval is 10 seconds by default, see WithApprovalPollInterval.
+func (s *pendingApprovalService) WaitForApproval(ctx context.Context, approvalID string) (*PendingApproval, error) {
+	interval := s.client.config.approvalPollInterval
+	if interval <= 0 {
+		interval = defaultApprovalPollInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		a, err := s.Get(ctx, approvalID)
+		if err != nil {
+			return nil, err
+		}
+		if a.Resolved() {
+			return a, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package bitgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestPendingApprovals(t *testing.T) {
+	filename := filepath.Join("testdata", "pendingapproval.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		requests []string
+		bodies   []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		switch r.Method {
+		case http.MethodPut:
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			w.Write(content)
+		case http.MethodGet:
+			if r.URL.Path == "/api/v1/pendingapprovals" {
+				w.Write([]byte(`{"pendingApprovals":[` + string(content) + `]}`))
+				return
+			}
+			w.Write(content)
+		}
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	want := bitgo.PendingApproval{
+		ID:                "55e8a1a5df8380e0e30e20c7",
+		WalletID:          "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr",
+		Enterprise:        "55e8a1a5df8380e0e30e20c5",
+		Creator:           "55e8a1a5df8380e0e30e20c4",
+		CreateDate:        time.Date(2015, 9, 3, 19, 55, 12, 11000000, time.UTC),
+		State:             bitgo.ApprovalStatePending,
+		ApprovalsRequired: 1,
+		Info: bitgo.ApprovalInfo{
+			Type: bitgo.ApprovalTypeTransactionRequest,
+			TransactionRequest: &bitgo.TransactionRequest{
+				RequestedAmount: 200000000,
+				Fee:             10000,
+				SourceWallet:    "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr",
+				Recipients: []bitgo.Recipient{
+					{Address: "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4", Amount: 200000000},
+				},
+				Message:         "payroll",
+				SequenceID:      "payroll-2015-09",
+				TriggeredPolicy: "55e8a1a5df8380e0e30e20c8",
+				Transaction:     "0100000001b6f2",
+			},
+		},
+	}
+
+	ctx := context.Background()
+	list, err := client.PendingApprovals.List(ctx, &bitgo.PendingApprovalsParams{WalletID: want.WalletID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 pending approval, got %d", len(list))
+	}
+	got := []*bitgo.PendingApproval{&list[0]}
+	for _, f := range []func() (*bitgo.PendingApproval, error){
+		func() (*bitgo.PendingApproval, error) { return client.PendingApprovals.Get(ctx, want.ID) },
+		func() (*bitgo.PendingApproval, error) {
+			return client.PendingApprovals.Approve(ctx, want.ID, &bitgo.ApproveParams{
+				OTP: "0000000",
+				Tx:  "0100000001b6f2",
+			})
+		},
+		func() (*bitgo.PendingApproval, error) { return client.PendingApprovals.Reject(ctx, want.ID, "0000000") },
+	} {
+		a, err := f()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, a)
+	}
+	for _, a := range got {
+		if len(a.Info.Raw) == 0 {
+			t.Errorf("raw JSON of approval info must be kept")
+		}
+		a.Info.Raw = nil
+		if !reflect.DeepEqual(*a, want) {
+			t.Errorf("should be %#v, not %#v", want, *a)
+		}
+	}
+
+	wantRequests := []string{
+		"GET /api/v1/pendingapprovals?walletId=2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr",
+		"GET /api/v1/pendingapprovals/55e8a1a5df8380e0e30e20c7",
+		"PUT /api/v1/pendingapprovals/55e8a1a5df8380e0e30e20c7",
+		"PUT /api/v1/pendingapprovals/55e8a1a5df8380e0e30e20c7",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected %v requests, got %v", wantRequests, requests)
+	}
+	wantBodies := []string{
+		`{"state":"approved","otp":"0000000","tx":"0100000001b6f2"}`,
+		`{"state":"rejected","otp":"0000000"}`,
+	}
+	if !reflect.DeepEqual(bodies, wantBodies) {
+		t.Errorf("expected %v bodies, got %v", wantBodies, bodies)
+	}
+}
+
+func TestPendingApprovalsParamsValidate(t *testing.T) {
+	tests := map[string]*bitgo.PendingApprovalsParams{
+		"nil params":     nil,
+		"no wallet":      {},
+		"wallet and ent": {WalletID: "wallet", Enterprise: "enterprise"},
+	}
+	client := bitgo.NewClient(bitgo.WithBaseURL("http://0.0.0.0:1"))
+	for name, params := range tests {
+		if _, err := client.PendingApprovals.List(context.Background(), params); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestWaitForApproval(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		polls int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		state := bitgo.ApprovalStatePending
+		if polls == 3 {
+			state = bitgo.ApprovalStateApproved
+		}
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]string{"id": "abc", "state": state})
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithApprovalPollInterval(time.Millisecond),
+	)
+	a, err := client.PendingApprovals.WaitForApproval(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if a.State != bitgo.ApprovalStateApproved || !a.Resolved() || polls != 3 {
+		t.Errorf("expected approval after 3 polls, got %q after %d", a.State, polls)
+	}
+	mu.Unlock()
+
+	client = bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithApprovalPollInterval(time.Hour),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = client.PendingApprovals.WaitForApproval(ctx, "abc"); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestErrorPendingApproval(t *testing.T) {
+	tests := map[string]struct {
+		body string
+		want string
+	}{
+		"approval id": {
+			body: `{"error":"exceeds a spending limit","pendingApproval":"abc","status":"pendingApproval"}`,
+			want: "abc",
+		},
+		"approval object": {
+			body: `{"error":"exceeds a spending limit","pendingApproval":{"id":"abc","state":"pending"}}`,
+			want: "abc",
+		},
+		"approval body": {
+			body: `{"id":"abc","state":"pending","info":{"type":"transactionRequest"}}`,
+			want: "abc",
+		},
+	}
+	for name, test := range tests {
+		e := bitgo.Error{Type: bitgo.ErrorTypeRequiresApproval, HTTPStatusCode: http.StatusAccepted, Body: test.body}
+		a, ok := e.PendingApproval()
+		if !ok {
+			t.Errorf("%s: expected pending approval", name)
+			continue
+		}
+		if a.ID != test.want || a.State != bitgo.ApprovalStatePending {
+			t.Errorf("%s: unexpected pending approval %#v", name, a)
+		}
+	}
+
+	for _, e := range []bitgo.Error{
+		{Type: bitgo.ErrorTypeRequiresApproval, Body: `{"error":"requires approval"}`},
+		{Type: bitgo.ErrorTypeRequiresApproval, Body: `not json`},
+		{Type: bitgo.ErrorTypeInvalidRequest, Body: `{"pendingApproval":"abc"}`},
+	} {
+		if a, ok := e.PendingApproval(); ok {
+			t.Errorf("expected no pending approval in %q, got %#v", e.Body, a)
+		}
+	}
+}
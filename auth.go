@@ -0,0 +1,138 @@
+package bitgo
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Authenticator authenticates API requests with a token and verifies API responses, see WithAuthenticator.
+// The token is the session token if the user logged in, otherwise it's the access token.
+type Authenticator interface {
+	// Authenticate adds credentials based on the token to the request.
+	Authenticate(req *http.Request, token string) error
+	// Verify checks the response to the request authenticated with the token.
+	// It may read the response body as long as it replaces it with an unread one.
+	Verify(req *http.Request, resp *http.Response, token string) error
+}
+
+// BearerAuthenticator sends the token in Authorization header. Responses aren't verified.
+// It's used by default.
+type BearerAuthenticator struct{}
+
+// Authenticate sets Authorization header to the bearer token.
+func (BearerAuthenticator) Authenticate(req *http.Request, token string) error {
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Verify accepts any response.
+func (BearerAuthenticator) Verify(req *http.Request, resp *http.Response, token string) error {
+	return nil
+}
+
+// hmacAuthVersion is the version of BitGo authentication protocol implemented by HMACAuthenticator.
+const hmacAuthVersion = "3.0"
+
+// HMACAuthenticator signs requests with HMAC-SHA256 keyed with the token, so the token itself is never sent,
+// only its SHA-256 hash identifies the session. Successful responses must be signed by BitGo with the same key,
+// otherwise they are rejected with an error whose IsSignatureMismatch method returns true.
+// Unsuccessful responses are verified only if they are signed, because rate limiting and temporary errors
+// can come unsigned from a proxy or a load balancer in front of the API.
+//
+// The request signature covers the method, the timestamp, the protocol version, the URL path with the query string,
+// and the body: "METHOD|timestamp|3.0|path?query|body".
+// The response signature covers the method, the response timestamp, the URL path with the query string,
+// the status code and the body: "METHOD|timestamp|path?query|status|body".
+type HMACAuthenticator struct{}
+
+// Authenticate sets Auth-Timestamp, HMAC and BitGo-Auth-Version headers,
+// and Authorization header to the bearer SHA-256 hash of the token.
+func (HMACAuthenticator) Authenticate(req *http.Request, token string) error {
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	subject := strings.Join([]string{req.Method, ts, hmacAuthVersion, req.URL.RequestURI(), string(body)}, "|")
+
+	h := sha256.Sum256([]byte(token))
+	req.Header.Set("Authorization", "Bearer "+hex.EncodeToString(h[:]))
+	req.Header.Set("Auth-Timestamp", ts)
+	req.Header.Set("HMAC", signHMAC(token, subject))
+	req.Header.Set("BitGo-Auth-Version", hmacAuthVersion)
+	return nil
+}
+
+// Verify checks HMAC and Timestamp headers of the response.
+// An unsigned response with a non-2xx status code is accepted so it can be classified as Error.
+// The response body is read to verify the signature and then replaced with an unread copy.
+func (HMACAuthenticator) Verify(req *http.Request, resp *http.Response, token string) error {
+	sig := resp.Header.Get("HMAC")
+	if sig == "" && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return nil
+	}
+
+	body, err := readBody(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	subject := strings.Join([]string{
+		req.Method,
+		resp.Header.Get("Timestamp"),
+		req.URL.RequestURI(),
+		strconv.Itoa(resp.StatusCode),
+		string(body),
+	}, "|")
+	if sig == "" || !hmac.Equal([]byte(sig), []byte(signHMAC(token, subject))) {
+		return Error{
+			Type:           ErrorTypeSignatureMismatch,
+			HTTPStatusCode: resp.StatusCode,
+			Body:           string(body),
+			Message:        "bitgo: response signature mismatch",
+		}
+	}
+	return nil
+}
+
+// signHMAC returns HMAC-SHA256 of the subject keyed with the token in hex format.
+func signHMAC(token, subject string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(subject))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestBody returns a copy of the request body leaving the body unread.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		body, err := readBody(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		return body, nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return readBody(rc)
+}
@@ -0,0 +1,174 @@
+package bitgo_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// sign returns HMAC-SHA256 of the subject parts joined with "|" keyed with the token.
+func sign(token string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hmacServer returns a server which checks HMAC signatures of requests and signs responses with the token.
+// Responses are signed with the tampered token if tamper is set.
+func hmacServer(t *testing.T, token string, tamper *bool, handler http.HandlerFunc) *httptest.Server {
+	h := sha256.Sum256([]byte(token))
+	tokenHash := hex.EncodeToString(h[:])
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		ts := r.Header.Get("Auth-Timestamp")
+		switch {
+		case r.Header.Get("Authorization") != "Bearer "+tokenHash:
+			t.Errorf("expected bearer token hash, got %q", r.Header.Get("Authorization"))
+		case r.Header.Get("BitGo-Auth-Version") != "3.0":
+			t.Errorf("expected auth version 3.0, got %q", r.Header.Get("BitGo-Auth-Version"))
+		case r.Header.Get("HMAC") != sign(token, r.Method, ts, "3.0", r.URL.RequestURI(), string(body)):
+			t.Errorf("invalid request signature %q", r.Header.Get("HMAC"))
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, r)
+		key := token
+		if tamper != nil && *tamper {
+			key = "tampered"
+		}
+		respTS := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+		w.Header().Set("Timestamp", respTS)
+		w.Header().Set("HMAC", sign(key, r.Method, respTS, r.URL.RequestURI(), strconv.Itoa(rec.Code), rec.Body.String()))
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+}
+
+func TestHMACAuthenticator(t *testing.T) {
+	const token = "swordfish"
+	var tamper bool
+	srv := hmacServer(t, token, &tamper, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Query().Get("limit") != "1" {
+			t.Errorf("expected limit query param, got %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"id":"2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr","label":"payouts"}`))
+	})
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken(token),
+		bitgo.WithAuthenticator(bitgo.HMACAuthenticator{}),
+	)
+	ctx := context.Background()
+	req, err := client.NewRequest(ctx, http.MethodPost, "wallet/2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr", url.Values{"limit": {"1"}}, map[string]string{"label": "payouts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wlt bitgo.Wallet
+	if _, err = client.Do(req, &wlt); err != nil {
+		t.Fatal(err)
+	}
+	if wlt.Label != "payouts" {
+		t.Errorf("unexpected wallet %+v", wlt)
+	}
+
+	tamper = true
+	_, err = client.Wallet.Get(ctx, "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr")
+	if e, ok := err.(bitgo.Error); !ok || !e.IsSignatureMismatch() {
+		t.Fatalf("expected signature mismatch error, got %#v", err)
+	}
+}
+
+func TestHMACAuthenticatorRetry(t *testing.T) {
+	const token = "swordfish"
+	var requests int
+	srv := hmacServer(t, token, nil, func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"service unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken(token),
+		bitgo.WithAuthenticator(bitgo.HMACAuthenticator{}),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{MaxAttempts: 2}),
+	)
+	if _, err := client.Wallet.Get(context.Background(), "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the request to be retried, got %d requests", requests)
+	}
+}
+
+func TestHMACAuthenticatorUnsignedError(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("swordfish"),
+		bitgo.WithAuthenticator(bitgo.HMACAuthenticator{}),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}),
+	)
+	_, err := client.Wallet.Get(context.Background(), "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr")
+	e, ok := err.(bitgo.Error)
+	if !ok || !e.IsRateLimited() || e.RetryAfter != time.Second {
+		t.Fatalf("expected rate limit error, got %#v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected the unsigned temporary error to be retried, got %d requests", n)
+	}
+}
+
+func TestBearerAuthenticatorSessionToken(t *testing.T) {
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("swordfish"),
+		bitgo.WithAuthenticator(bitgo.BearerAuthenticator{}),
+	)
+	ctx := context.Background()
+	client.Wallet.Get(ctx, "wallet")
+	client.SetSessionToken("v2xsession")
+	client.Wallet.Get(ctx, "wallet")
+	client.SetSessionToken("")
+	client.Wallet.Get(ctx, "wallet")
+
+	want := "Bearer swordfish,Bearer v2xsession,Bearer swordfish"
+	if got := strings.Join(auth, ","); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -1,14 +1,19 @@
 // Package bitgo is a client for BitGo API v1.
+// Coin-scoped endpoints of BitGo API v2 are available through Client.Coin, e.g., client.Coin("tbtc").Wallet.
 package bitgo
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -24,6 +29,19 @@ type Config struct {
 	httpClient  *http.Client
 	baseURL     string
 	accessToken string
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	middleware  []Middleware
+	stack       []Middleware
+	logger      Logger
+	// authenticator authenticates requests with the access or session token, BearerAuthenticator by default.
+	authenticator Authenticator
+	// maxResponseSize is a maximum size of a response body in bytes, zero or less means no limit.
+	maxResponseSize int64
+	// approvalPollInterval is how often PendingApprovals.WaitForApproval checks an approval.
+	approvalPollInterval time.Duration
+	// otpProvider returns a one-time password to unlock the session when a request needs it.
+	otpProvider func(ctx context.Context) (string, error)
 }
 
 // ConfigOption configures how we set up the Client.
@@ -51,10 +69,105 @@ func WithAccesToken(token string) ConfigOption {
 	}
 }
 
+// WithAuthenticator sets how requests are authenticated and responses are verified,
+// e.g., HMACAuthenticator signs requests instead of sending the token. BearerAuthenticator is used by default.
+// It has no effect if the middleware stack is replaced with WithMiddlewareStack, see Authenticate.
+func WithAuthenticator(a Authenticator) ConfigOption {
+	return func(c *Config) {
+		c.authenticator = a
+	}
+}
+
+// WithRetryPolicy configures Client to retry requests failed due to
+// temporary API errors or rate limiting, see RetryPolicy.
+func WithRetryPolicy(p RetryPolicy) ConfigOption {
+	return func(c *Config) {
+		c.retryPolicy = p
+	}
+}
+
+// WithRateLimiter configures Client to throttle outgoing requests.
+// The same limiter can be shared by Clients which use the same access token.
+func WithRateLimiter(l *RateLimiter) ConfigOption {
+	return func(c *Config) {
+		c.rateLimiter = l
+	}
+}
+
+// WithMiddleware adds middleware to Client. Requests pass through them in the order they were added
+// before reaching the built-in middleware stack, see DefaultMiddleware.
+// Each retry attempt passes through the whole chain.
+func WithMiddleware(mw ...Middleware) ConfigOption {
+	return func(c *Config) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMiddlewareStack replaces the built-in middleware stack returned by DefaultMiddleware,
+// so bearer authentication, JSON decoding and error classification can be replaced or reordered.
+// Requests are then authenticated only by the stack: NewRequest doesn't add the access token,
+// so the stack should include BearerAuth or Authenticate unless it authenticates requests differently.
+func WithMiddlewareStack(mw ...Middleware) ConfigOption {
+	return func(c *Config) {
+		c.stack = mw
+	}
+}
+
+// WithLogger configures Client to log API requests: method, path, status, duration and
+// BitGo request ID. Request and response bodies are logged at debug level.
+// Secrets such as passphrases, tokens, private keys and OTP codes are redacted.
+func WithLogger(l Logger) ConfigOption {
+	return func(c *Config) {
+		c.logger = l
+	}
+}
+
+// WithMaxResponseSize limits a size of response bodies, 32 MB by default.
+// Reading a larger body fails with ErrResponseTooLarge. Zero or negative n means there is no limit.
+func WithMaxResponseSize(n int64) ConfigOption {
+	return func(c *Config) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithApprovalPollInterval sets how often PendingApprovals.WaitForApproval checks
+// whether an approval is resolved, 10 seconds by default.
+func WithApprovalPollInterval(d time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.approvalPollInterval = d
+	}
+}
+
+// WithOTPProvider configures Client to unlock the session and retry a request once
+// when it fails because the session needs an unlock or OTP, see Error.IsUnlockRequired and Error.IsOTPRequired.
+// The provider is called to get a one-time password, e.g., by prompting an operator.
+// Concurrent requests which need an unlock of the same session share a single call of the provider.
+func WithOTPProvider(provider func(ctx context.Context) (string, error)) ConfigOption {
+	return func(c *Config) {
+		c.otpProvider = provider
+	}
+}
+
 // Client manages communication with the BitGo REST-ful API.
 type Client struct {
 	config Config
-	Wallet *walletService
+	doer   Doer
+	// basePath is the path of the base URL, e.g., "/bitgo" of Express proxy at https://example.com/bitgo/.
+	basePath string
+	// mu guards the session token which is set by User.Login and API v2 coins returned by Coin.
+	mu           sync.RWMutex
+	sessionToken string
+	coins        map[string]*Coin
+	// unlockMu guards unlocks in progress and the time of the last unlock by session token,
+	// so concurrent requests which need an unlock share a single OTP and unlock call.
+	unlockMu   sync.Mutex
+	unlocking  map[string]*unlockCall
+	unlockedAt map[string]time.Time
+
+	Wallet           WalletService
+	Fee              FeeService
+	PendingApprovals PendingApprovalService
+	User             UserService
 }
 
 // NewClient returns a Client which can be configured with config options.
@@ -62,29 +175,69 @@ type Client struct {
 func NewClient(options ...ConfigOption) *Client {
 	c := Client{
 		config: Config{
-			httpClient: http.DefaultClient,
-			baseURL:    defaultBaseURL,
+			httpClient:      http.DefaultClient,
+			baseURL:         defaultBaseURL,
+			authenticator:   BearerAuthenticator{},
+			maxResponseSize: defaultMaxResponseSize,
 		},
 	}
 
 	c.Wallet = &walletService{client: &c}
+	c.Fee = &feeService{client: &c}
+	c.PendingApprovals = &pendingApprovalService{client: &c}
+	c.User = &userService{client: &c}
 
 	for _, opt := range options {
 		opt(&c.config)
 	}
+
+	if u, err := url.Parse(c.config.baseURL); err == nil {
+		c.basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	// The stack is left nil unless it's replaced, so NewRequest knows whether to add the access token.
+	stack := c.config.stack
+	if stack == nil {
+		stack = defaultMiddleware(c.config.authenticator, c.config.accessToken)
+	}
+	var mw []Middleware
+	if c.config.logger != nil {
+		mw = append(mw, logRequests(c.config.logger))
+	}
+	mw = append(mw, c.config.middleware...)
+	mw = append(mw, stack...)
+	c.doer = chain(DoerFunc(c.send), mw...)
 	return &c
 }
 
-// NewRequest creates Request to access BitGo API.
+// NewRequest creates Request to access BitGo API v1.
+// The request is authenticated with the bearer session token if there is one, see User.Login,
+// otherwise with the access token unless the middleware stack is replaced with WithMiddlewareStack.
+// The Authenticate middleware turns the token into the credentials of the configured Authenticator,
+// so the request can be sent with another http.Client as long as BearerAuthenticator is used.
 // API path must not start or end with slash. Query string params are optional.
 // If specified, the value pointed to by body is JSON encoded and included
 // as the request body.
 func (c *Client) NewRequest(ctx context.Context, method, path string, params url.Values, body interface{}) (*http.Request, error) {
+	return c.newRequest(ctx, method, "api/v1/"+path, params, body)
+}
+
+// NewCoinRequest creates Request to access coin-scoped endpoints of BitGo API v2,
+// e.g., path "wallet/{id}" of coin "tbtc" is sent to /api/v2/tbtc/wallet/{id}, see Client.Coin.
+// Otherwise it works as NewRequest.
+func (c *Client) NewCoinRequest(ctx context.Context, coin, method, path string, params url.Values, body interface{}) (*http.Request, error) {
+	if coin == "" {
+		return nil, errors.New("bitgo: coin must not be empty")
+	}
+	return c.newRequest(ctx, method, fmt.Sprintf("api/v2/%s/%s", url.PathEscape(coin), path), params, body)
+}
+
+// newRequest creates Request to access the API path which includes the API version, e.g., "api/v1/wallet".
+func (c *Client) newRequest(ctx context.Context, method, path string, params url.Values, body interface{}) (*http.Request, error) {
 	var urlStr string
 	if params != nil {
-		urlStr = fmt.Sprintf("%s/api/v1/%s?%s", c.config.baseURL, path, params.Encode())
+		urlStr = fmt.Sprintf("%s/%s?%s", c.config.baseURL, path, params.Encode())
 	} else {
-		urlStr = fmt.Sprintf("%s/api/v1/%s", c.config.baseURL, path)
+		urlStr = fmt.Sprintf("%s/%s", c.config.baseURL, path)
 	}
 
 	var b []byte
@@ -94,7 +247,6 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, params url
 			return nil, err
 		}
 	}
-	debug("creating request, method: %s, url: %s, body: %s", method, urlStr, b)
 
 	req, err := http.NewRequest(method, urlStr, bytes.NewReader(b))
 	if err != nil {
@@ -104,54 +256,190 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, params url
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	if c.config.accessToken != "" {
-		bearer := fmt.Sprintf("Bearer %s", c.config.accessToken)
-		req.Header.Set("Authorization", bearer)
+	switch token := c.SessionToken(); {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case c.config.accessToken != "" && c.config.stack == nil:
+		req.Header.Set("Authorization", "Bearer "+c.config.accessToken)
 	}
 
 	return req, nil
 }
 
+// SessionToken returns the session token set by User.Login or SetSessionToken.
+func (c *Client) SessionToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sessionToken
+}
+
+// SetSessionToken sets the token which authenticates the following requests instead of the access token,
+// e.g., to restore a session saved after User.Login. An empty token ends the session on the client side.
+func (c *Client) SetSessionToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionToken = token
+}
+
 // Do uses Client's HTTP client to execute the Request and
 // unmarshals the Response into v.
 // It also handles unmarshaling errors returned by the API.
+// The request passes through the middleware chain, see WithMiddleware.
+// Failed requests are retried according to the retry policy, see WithRetryPolicy.
+// Each attempt waits for its turn if a rate limiter is configured, see WithRateLimiter.
+// A request which needs an unlock is retried once after the session is unlocked, see WithOTPProvider.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.config.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+	sent := time.Now()
+	resp, err := c.do(req, v)
+	if !c.canUnlock(req.Context(), err) {
+		return resp, err
+	}
+	if err = c.unlock(req.Context(), sent); err != nil {
+		return resp, err
+	}
+	if req, err = rewindBody(req); err != nil {
+		return resp, err
+	}
+	return c.do(req, v)
+}
+
+// noUnlockKey is a context key which marks requests made to log in or unlock the session,
+// so they never trigger an unlock.
+type noUnlockKey struct{}
+
+// canUnlock returns true if the request failed because the session needs an unlock
+// and it can be unlocked with OTP from the provider.
+func (c *Client) canUnlock(ctx context.Context, err error) bool {
+	if c.config.otpProvider == nil || ctx.Value(noUnlockKey{}) != nil {
+		return false
+	}
+	e, ok := err.(Error)
+	return ok && (e.IsUnlockRequired() || e.IsOTPRequired())
+}
+
+// unlockCall is an unlock of the session in progress which concurrent requests wait for.
+type unlockCall struct {
+	done chan struct{}
+	err  error
+}
+
+// unlock unlocks the session which a request sent at the given time found locked.
+// Concurrent requests wait for a single unlock of the session,
+// and the session isn't unlocked again if it was unlocked after the request had been sent.
+func (c *Client) unlock(ctx context.Context, sent time.Time) error {
+	token := c.SessionToken()
+	c.unlockMu.Lock()
+	if c.unlockedAt[token].After(sent) {
+		c.unlockMu.Unlock()
+		return nil
+	}
+	if call, ok := c.unlocking[token]; ok {
+		c.unlockMu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if c.unlocking == nil {
+		c.unlocking = make(map[string]*unlockCall)
+		c.unlockedAt = make(map[string]time.Time)
 	}
-	defer resp.Body.Close()
+	call := &unlockCall{done: make(chan struct{})}
+	c.unlocking[token] = call
+	c.unlockMu.Unlock()
+
+	call.err = c.unlockSession(ctx)
 
-	body, err := ioutil.ReadAll(resp.Body)
+	c.unlockMu.Lock()
+	delete(c.unlocking, token)
+	if call.err == nil {
+		c.unlockedAt[token] = time.Now()
+	}
+	c.unlockMu.Unlock()
+	close(call.done)
+	return call.err
+}
+
+// unlockSession unlocks the session with OTP from the provider for the API default duration.
+func (c *Client) unlockSession(ctx context.Context) error {
+	otp, err := c.config.otpProvider(ctx)
 	if err != nil {
-		return resp, err
+		return fmt.Errorf("bitgo: failed to get otp: %w", err)
 	}
-	debug("server response, status: %s, header: %s, body: %s", resp.Status, resp.Header, body)
+	u := userService{client: c}
+	_, err = u.Unlock(ctx, otp, 0)
+	return err
+}
 
-	if resp.StatusCode == http.StatusOK {
-		err = json.Unmarshal(body, v)
-		return resp, err
+// apiPath returns the URL path of the request relative to the base URL, e.g., "/api/v2/tbtc/wallet/abc".
+func (c *Client) apiPath(req *http.Request) string {
+	return strings.TrimPrefix(req.URL.Path, c.basePath)
+}
+
+// do sends the request retrying it according to the retry policy.
+func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
+	p := &c.config.retryPolicy
+	for attempt := 1; ; attempt++ {
+		if c.config.rateLimiter != nil {
+			if err := c.config.rateLimiter.wait(req.Context(), req, c.apiPath(req)); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.doer.Do(req, v)
+		if resp != nil {
+			// The body is usually consumed by middleware, closing it again is harmless.
+			resp.Body.Close()
+		}
+		wait, ok := p.backoff(req, attempt, err)
+		if !ok {
+			return resp, err
+		}
+		if c.config.logger != nil {
+			c.config.logger.Log(req.Context(), slog.LevelWarn, "bitgo: retrying request",
+				"method", req.Method,
+				"path", req.URL.Path,
+				"attempt", attempt,
+				"wait", wait,
+			)
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(RetryAttempt{
+				Request: req,
+				Attempt: attempt,
+				Err:     err,
+				Wait:    wait,
+			})
+		}
+
+		if err = sleep(req.Context(), wait); err != nil {
+			return resp, err
+		}
+		if req, err = rewindBody(req); err != nil {
+			return resp, err
+		}
 	}
+}
 
-	e := Error{
-		HTTPStatusCode: resp.StatusCode,
-		Body:           string(body),
+// send sends the request using Client's HTTP client.
+// It's the innermost Doer in the middleware chain which leaves the response body unread.
+func (c *Client) send(req *http.Request, v interface{}) (*http.Response, error) {
+	if c.config.logger != nil {
+		return logBodies(c.config.logger, req, c.roundTrip)
 	}
-	_ = json.Unmarshal(body, &e)
+	return c.roundTrip(req)
+}
 
-	switch resp.StatusCode {
-	case http.StatusAccepted:
-		e.Type = ErrorTypeRequiresApproval
-	case http.StatusBadRequest:
-		e.Type = ErrorTypeInvalidRequest
-	case http.StatusUnauthorized, http.StatusForbidden:
-		e.Type = ErrorTypeAuthentication
-	case http.StatusNotFound:
-		e.Type = ErrorTypeNotFound
-	case http.StatusTooManyRequests:
-		e.Type = ErrorTypeRateLimit
-	default:
-		e.Type = ErrorTypeAPI
+// roundTrip sends the request and limits the size of the response body.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.config.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.maxResponseSize > 0 {
+		resp.Body = limitBody(resp.Body, c.config.maxResponseSize)
 	}
-	return resp, e
+	return resp, nil
 }
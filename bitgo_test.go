@@ -1,9 +1,17 @@
 package bitgo_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/marselester/bitgo-v1"
@@ -28,6 +36,30 @@ func TestErrorResponse(t *testing.T) {
 				RequestID:      "bj9h0dap1723kadrsnfkvsinz",
 			},
 		},
+		{
+			name:       "401 needs unlock error",
+			body:       `{"error":"needs unlock","needsUnlock":true,"requestId":"bj9h0dap1723kadrsnfkvsinz"}`,
+			statusCode: http.StatusUnauthorized,
+			want: bitgo.Error{
+				Type:           bitgo.ErrorTypeUnlockRequired,
+				HTTPStatusCode: http.StatusUnauthorized,
+				Body:           `{"error":"needs unlock","needsUnlock":true,"requestId":"bj9h0dap1723kadrsnfkvsinz"}` + "\n",
+				Message:        "needs unlock",
+				RequestID:      "bj9h0dap1723kadrsnfkvsinz",
+			},
+		},
+		{
+			name:       "401 needs OTP error",
+			body:       `{"error":"needs otp","needsOTP":true,"requestId":"bj9h0dap1723kadrsnfkvsinz"}`,
+			statusCode: http.StatusUnauthorized,
+			want: bitgo.Error{
+				Type:           bitgo.ErrorTypeOTPRequired,
+				HTTPStatusCode: http.StatusUnauthorized,
+				Body:           `{"error":"needs otp","needsOTP":true,"requestId":"bj9h0dap1723kadrsnfkvsinz"}` + "\n",
+				Message:        "needs otp",
+				RequestID:      "bj9h0dap1723kadrsnfkvsinz",
+			},
+		},
 		{
 			name:       "500 temporary API error",
 			body:       "some internal server error",
@@ -95,3 +127,253 @@ func TestErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRequestAccessToken(t *testing.T) {
+	client := bitgo.NewClient(bitgo.WithAccesToken("swordfish"))
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "wallet", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer swordfish" {
+		t.Errorf("expected access token, got %q", got)
+	}
+
+	client.SetSessionToken("v2xsession")
+	if req, err = client.NewRequest(context.Background(), http.MethodGet, "wallet", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer v2xsession" {
+		t.Errorf("expected session token, got %q", got)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":0,"total":0,"start":0,"unspents":[]}`))
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithMaxResponseSize(10),
+	)
+	err := client.Wallet.Unspents(context.Background(), "", nil, func(*bitgo.UnspentList) {})
+	if err != bitgo.ErrResponseTooLarge {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	client = bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithMaxResponseSize(45),
+	)
+	if err = client.Wallet.Unspents(context.Background(), "", nil, func(*bitgo.UnspentList) {}); err != nil {
+		t.Fatalf("body fits the limit exactly: %v", err)
+	}
+
+	client = bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithMaxResponseSize(0),
+	)
+	if err = client.Wallet.Unspents(context.Background(), "", nil, func(*bitgo.UnspentList) {}); err != nil {
+		t.Fatalf("zero limit means no limit: %v", err)
+	}
+}
+
+func TestMaxResponseSizeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"service unavailable","details":"`))
+		w.Write(bytes.Repeat([]byte("x"), 100<<10))
+		w.Write([]byte(`"}`))
+	}))
+	defer srv.Close()
+
+	for _, size := range []int64{10, 1 << 20} {
+		client := bitgo.NewClient(
+			bitgo.WithBaseURL(srv.URL),
+			bitgo.WithMaxResponseSize(size),
+		)
+		err := client.Wallet.Unspents(context.Background(), "", nil, func(*bitgo.UnspentList) {})
+		e, ok := err.(bitgo.Error)
+		if !ok || !e.IsTemporary() || e.HTTPStatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected temporary error despite truncated body, got %v", err)
+		}
+		if len(e.Body) > 64<<10 {
+			t.Errorf("expected error body to be truncated, got %d bytes", len(e.Body))
+		}
+	}
+}
+
+func TestOTPProvider(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		unlocked bool
+		requests []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		b, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(b))
+		switch {
+		case r.URL.Path == "/api/v1/user/unlock":
+			var p struct {
+				OTP string `json:"otp"`
+			}
+			json.Unmarshal(b, &p)
+			if p.OTP != "0000000" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"needs otp","needsOTP":true}`))
+				return
+			}
+			unlocked = true
+			w.Write([]byte(`{"session":{}}`))
+		case !unlocked:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"needs unlock","needsUnlock":true}`))
+		default:
+			w.Write([]byte(`{"status":"accepted","hash":"abc"}`))
+		}
+	}))
+	defer srv.Close()
+
+	var otps []string
+	otp := "0000000"
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithOTPProvider(func(ctx context.Context) (string, error) {
+			otps = append(otps, otp)
+			return otp, nil
+		}),
+	)
+	params := &bitgo.SendCoinsParams{
+		Address:          "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4",
+		Amount:           1000,
+		WalletPassphrase: "root",
+	}
+	res, err := client.Wallet.SendCoins(context.Background(), "wallet", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TxID != "abc" || len(otps) != 1 {
+		t.Errorf("expected the coins to be sent after unlock, got %#v after %d OTPs", res, len(otps))
+	}
+	send := `POST /api/v1/wallet/wallet/sendcoins {"address":"2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4","amount":1000,"walletPassphrase":"root"}`
+	wantRequests := []string{
+		send,
+		`POST /api/v1/user/unlock {"otp":"0000000"}`,
+		send,
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected %v, got %v", wantRequests, requests)
+	}
+
+	// The unlock fails with needs OTP error which must not trigger another unlock.
+	mu.Lock()
+	unlocked, requests = false, nil
+	mu.Unlock()
+	otp, otps = "1234567", nil
+	_, err = client.Wallet.SendCoins(context.Background(), "wallet", params)
+	if e, ok := err.(bitgo.Error); !ok || !e.IsOTPRequired() {
+		t.Fatalf("expected needs OTP error, got %#v", err)
+	}
+	if len(otps) != 1 || len(requests) != 2 {
+		t.Errorf("expected a single unlock attempt, got %d OTPs and %v", len(otps), requests)
+	}
+
+	// The provider error is returned.
+	client = bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithOTPProvider(func(ctx context.Context) (string, error) {
+			return "", errors.New("operator is away")
+		}),
+	)
+	_, err = client.Wallet.SendCoins(context.Background(), "wallet", params)
+	if err == nil || !strings.Contains(err.Error(), "operator is away") {
+		t.Errorf("expected provider error, got %v", err)
+	}
+}
+
+func TestOTPProviderConcurrent(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		unlocked bool
+		unlocks  int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/api/v1/user/unlock":
+			unlocks++
+			unlocked = true
+			w.Write([]byte(`{"session":{}}`))
+		case !unlocked:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"needs unlock","needsUnlock":true}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	const n = 10
+	var (
+		otps    int32
+		failed  sync.WaitGroup
+		release = make(chan struct{})
+	)
+	failed.Add(n)
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithOTPProvider(func(ctx context.Context) (string, error) {
+			atomic.AddInt32(&otps, 1)
+			<-release
+			return "0000000", nil
+		}),
+		// The OTP is provided only after every request failed, so all of them need the unlock at the same time.
+		bitgo.WithMiddleware(func(next bitgo.Doer) bitgo.Doer {
+			var once sync.Map
+			return bitgo.DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+				resp, err := next.Do(req, v)
+				if e, ok := err.(bitgo.Error); ok && e.IsUnlockRequired() {
+					if _, seen := once.LoadOrStore(req.Context(), true); !seen {
+						failed.Done()
+					}
+				}
+				return resp, err
+			})
+		}),
+	)
+	go func() {
+		failed.Wait()
+		close(release)
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			_, err := client.Wallet.Get(ctx, "wallet")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if n := atomic.LoadInt32(&otps); n != 1 || unlocks != 1 {
+		t.Errorf("expected a single unlock, got %d OTPs and %d unlocks", n, unlocks)
+	}
+}
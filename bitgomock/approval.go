@@ -0,0 +1,73 @@
+package bitgomock
+
+import (
+	"context"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+var _ bitgo.PendingApprovalService = (*PendingApprovalService)(nil)
+
+// PendingApprovalService is a mock of bitgo.PendingApprovalService.
+// A method returns the canned response unless its Func field is set.
+type PendingApprovalService struct {
+	recorder
+
+	// PendingApprovals are approvals returned by List.
+	PendingApprovals []bitgo.PendingApproval
+	// PendingApproval is an approval returned by Get, Approve, Reject and WaitForApproval.
+	PendingApproval *bitgo.PendingApproval
+	// Err is an error returned by all methods.
+	Err error
+
+	ListFunc            func(ctx context.Context, params *bitgo.PendingApprovalsParams) ([]bitgo.PendingApproval, error)
+	GetFunc             func(ctx context.Context, approvalID string) (*bitgo.PendingApproval, error)
+	ApproveFunc         func(ctx context.Context, approvalID string, params *bitgo.ApproveParams) (*bitgo.PendingApproval, error)
+	RejectFunc          func(ctx context.Context, approvalID, otp string) (*bitgo.PendingApproval, error)
+	WaitForApprovalFunc func(ctx context.Context, approvalID string) (*bitgo.PendingApproval, error)
+}
+
+// List returns PendingApprovals.
+func (m *PendingApprovalService) List(ctx context.Context, params *bitgo.PendingApprovalsParams) ([]bitgo.PendingApproval, error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, params)
+	}
+	return m.PendingApprovals, m.Err
+}
+
+// Get returns PendingApproval.
+func (m *PendingApprovalService) Get(ctx context.Context, approvalID string) (*bitgo.PendingApproval, error) {
+	m.record("Get", approvalID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, approvalID)
+	}
+	return m.PendingApproval, m.Err
+}
+
+// Approve returns PendingApproval.
+func (m *PendingApprovalService) Approve(ctx context.Context, approvalID string, params *bitgo.ApproveParams) (*bitgo.PendingApproval, error) {
+	m.record("Approve", approvalID, params)
+	if m.ApproveFunc != nil {
+		return m.ApproveFunc(ctx, approvalID, params)
+	}
+	return m.PendingApproval, m.Err
+}
+
+// Reject returns PendingApproval.
+func (m *PendingApprovalService) Reject(ctx context.Context, approvalID, otp string) (*bitgo.PendingApproval, error) {
+	m.record("Reject", approvalID, otp)
+	if m.RejectFunc != nil {
+		return m.RejectFunc(ctx, approvalID, otp)
+	}
+	return m.PendingApproval, m.Err
+}
+
+// WaitForApproval returns PendingApproval.
+func (m *PendingApprovalService) WaitForApproval(ctx context.Context, approvalID string) (*bitgo.PendingApproval, error) {
+	m.record("WaitForApproval", approvalID)
+	if m.WaitForApprovalFunc != nil {
+		return m.WaitForApprovalFunc(ctx, approvalID)
+	}
+	return m.PendingApproval, m.Err
+}
@@ -0,0 +1,52 @@
+// Package bitgomock provides mock implementations of bitgo services,
+// so code using bitgo.Client can be unit tested without BitGo API server.
+// The mocks record calls and return canned responses.
+//
+//	m := &bitgomock.WalletService{
+//		TxInfos: []bitgo.TxInfo{{TxID: "abc"}},
+//	}
+//	c := bitgo.NewClient()
+//	c.Wallet = m
+package bitgomock
+
+import "sync"
+
+// Call is a recorded method call.
+type Call struct {
+	// Method is a name of the called method, e.g., "Unspents".
+	Method string
+	// Args are arguments passed to the method except the context.
+	Args []interface{}
+}
+
+// recorder records method calls, it's safe for concurrent use.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns all recorded method calls in order they were made.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns recorded calls of the method.
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
@@ -0,0 +1,57 @@
+package bitgomock
+
+import (
+	"context"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+var _ bitgo.CoinWalletService = (*CoinWalletService)(nil)
+
+// CoinWalletService is a mock of bitgo.CoinWalletService.
+// A method returns the canned response unless its Func field is set.
+type CoinWalletService struct {
+	recorder
+
+	// Wallet is a wallet returned by Get.
+	Wallet *bitgo.Wallet
+	// UnspentLists are pages returned by UnspentsPager.
+	UnspentLists []*bitgo.UnspentList
+	// TxInfo is a transaction returned by Consolidate.
+	TxInfo *bitgo.TxInfo
+	// Err is an error returned by all methods.
+	Err error
+
+	GetFunc           func(ctx context.Context, walletID string) (*bitgo.Wallet, error)
+	UnspentsPagerFunc func(walletID string, params *bitgo.UnspentsParams) *bitgo.Pager[bitgo.Unspent]
+	ConsolidateFunc   func(ctx context.Context, walletID string, bodyParams *bitgo.WalletConsolidateParams) (*bitgo.TxInfo, error)
+}
+
+// Get returns Wallet.
+func (m *CoinWalletService) Get(ctx context.Context, walletID string) (*bitgo.Wallet, error) {
+	m.record("Get", walletID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, walletID)
+	}
+	return m.Wallet, m.Err
+}
+
+// UnspentsPager returns a Pager over UnspentLists.
+func (m *CoinWalletService) UnspentsPager(walletID string, params *bitgo.UnspentsParams) *bitgo.Pager[bitgo.Unspent] {
+	m.record("UnspentsPager", walletID, params)
+	if m.UnspentsPagerFunc != nil {
+		return m.UnspentsPagerFunc(walletID, params)
+	}
+	return listPager(m.UnspentLists, m.Err, func(list *bitgo.UnspentList) *bitgo.Page[bitgo.Unspent] {
+		return &bitgo.Page[bitgo.Unspent]{ListMeta: list.ListMeta, Items: list.Unspents}
+	})
+}
+
+// Consolidate returns TxInfo.
+func (m *CoinWalletService) Consolidate(ctx context.Context, walletID string, bodyParams *bitgo.WalletConsolidateParams) (*bitgo.TxInfo, error) {
+	m.record("Consolidate", walletID, bodyParams)
+	if m.ConsolidateFunc != nil {
+		return m.ConsolidateFunc(ctx, walletID, bodyParams)
+	}
+	return m.TxInfo, m.Err
+}
@@ -0,0 +1,31 @@
+package bitgomock
+
+import (
+	"context"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+var _ bitgo.FeeService = (*FeeService)(nil)
+
+// FeeService is a mock of bitgo.FeeService.
+// A method returns the canned response unless its Func field is set.
+type FeeService struct {
+	recorder
+
+	// FeeEstimate is an estimate returned by Estimate.
+	FeeEstimate *bitgo.FeeEstimate
+	// Err is an error returned by all methods.
+	Err error
+
+	EstimateFunc func(ctx context.Context, numBlocks int) (*bitgo.FeeEstimate, error)
+}
+
+// Estimate returns FeeEstimate.
+func (m *FeeService) Estimate(ctx context.Context, numBlocks int) (*bitgo.FeeEstimate, error) {
+	m.record("Estimate", numBlocks)
+	if m.EstimateFunc != nil {
+		return m.EstimateFunc(ctx, numBlocks)
+	}
+	return m.FeeEstimate, m.Err
+}
@@ -0,0 +1,111 @@
+package bitgomock
+
+import (
+	"context"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+var _ bitgo.UserService = (*UserService)(nil)
+
+// UserService is a mock of bitgo.UserService.
+// A method returns the canned response unless its Func field is set.
+type UserService struct {
+	recorder
+
+	// LoginResult is a result returned by Login.
+	LoginResult *bitgo.LoginResult
+	// SessionInfo is a session returned by Session and Unlock.
+	SessionInfo *bitgo.Session
+	// User is a user returned by Me.
+	User *bitgo.User
+	// AccessToken is an access token returned by CreateAccessToken.
+	AccessToken *bitgo.AccessToken
+	// AccessTokenList is a list of access tokens returned by AccessTokens.
+	AccessTokenList []bitgo.AccessToken
+	// Err is an error returned by all methods.
+	Err error
+
+	LoginFunc   func(ctx context.Context, params *bitgo.LoginParams) (*bitgo.LoginResult, error)
+	LogoutFunc  func(ctx context.Context) error
+	SessionFunc func(ctx context.Context) (*bitgo.Session, error)
+	UnlockFunc  func(ctx context.Context, otp string, duration time.Duration) (*bitgo.Session, error)
+	MeFunc      func(ctx context.Context) (*bitgo.User, error)
+
+	CreateAccessTokenFunc func(ctx context.Context, params *bitgo.AccessTokenParams) (*bitgo.AccessToken, error)
+	AccessTokensFunc      func(ctx context.Context) ([]bitgo.AccessToken, error)
+	RevokeAccessTokenFunc func(ctx context.Context, tokenID string) error
+}
+
+// Login returns LoginResult.
+func (m *UserService) Login(ctx context.Context, params *bitgo.LoginParams) (*bitgo.LoginResult, error) {
+	m.record("Login", params)
+	if m.LoginFunc != nil {
+		return m.LoginFunc(ctx, params)
+	}
+	return m.LoginResult, m.Err
+}
+
+// Logout returns Err.
+func (m *UserService) Logout(ctx context.Context) error {
+	m.record("Logout")
+	if m.LogoutFunc != nil {
+		return m.LogoutFunc(ctx)
+	}
+	return m.Err
+}
+
+// Session returns SessionInfo.
+func (m *UserService) Session(ctx context.Context) (*bitgo.Session, error) {
+	m.record("Session")
+	if m.SessionFunc != nil {
+		return m.SessionFunc(ctx)
+	}
+	return m.SessionInfo, m.Err
+}
+
+// Unlock returns SessionInfo.
+func (m *UserService) Unlock(ctx context.Context, otp string, duration time.Duration) (*bitgo.Session, error) {
+	m.record("Unlock", otp, duration)
+	if m.UnlockFunc != nil {
+		return m.UnlockFunc(ctx, otp, duration)
+	}
+	return m.SessionInfo, m.Err
+}
+
+// Me returns User.
+func (m *UserService) Me(ctx context.Context) (*bitgo.User, error) {
+	m.record("Me")
+	if m.MeFunc != nil {
+		return m.MeFunc(ctx)
+	}
+	return m.User, m.Err
+}
+
+// CreateAccessToken returns AccessToken.
+func (m *UserService) CreateAccessToken(ctx context.Context, params *bitgo.AccessTokenParams) (*bitgo.AccessToken, error) {
+	m.record("CreateAccessToken", params)
+	if m.CreateAccessTokenFunc != nil {
+		return m.CreateAccessTokenFunc(ctx, params)
+	}
+	return m.AccessToken, m.Err
+}
+
+// AccessTokens returns AccessTokenList.
+func (m *UserService) AccessTokens(ctx context.Context) ([]bitgo.AccessToken, error) {
+	m.record("AccessTokens")
+	if m.AccessTokensFunc != nil {
+		return m.AccessTokensFunc(ctx)
+	}
+	return m.AccessTokenList, m.Err
+}
+
+// RevokeAccessToken returns Err.
+func (m *UserService) RevokeAccessToken(ctx context.Context, tokenID string) error {
+	m.record("RevokeAccessToken", tokenID)
+	if m.RevokeAccessTokenFunc != nil {
+		return m.RevokeAccessTokenFunc(ctx, tokenID)
+	}
+	return m.Err
+}
@@ -0,0 +1,231 @@
+package bitgomock
+
+import (
+	"context"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+var _ bitgo.WalletService = (*WalletService)(nil)
+
+// WalletService is a mock of bitgo.WalletService.
+// A method returns the canned response unless its Func field is set.
+type WalletService struct {
+	recorder
+
+	// WalletLists are pages returned by List.
+	WalletLists []*bitgo.WalletList
+	// Wallet is a wallet returned by Get.
+	Wallet *bitgo.Wallet
+	// AddressLists are pages returned by Addresses.
+	AddressLists []*bitgo.AddressList
+	// AddressInfo is an address returned by CreateAddress and Address.
+	AddressInfo *bitgo.Address
+	// TransactionLists are pages returned by Transactions.
+	TransactionLists []*bitgo.TransactionList
+	// TransactionInfo is a transaction returned by Transaction and TransactionBySequenceID.
+	TransactionInfo *bitgo.Transaction
+	// UnspentLists are pages returned by Unspents and UnspentsPager.
+	UnspentLists []*bitgo.UnspentList
+	// UnsignedTx is a transaction returned by CreateTransaction.
+	UnsignedTx *bitgo.UnsignedTx
+	// SignedTx is a transaction returned by SignTransaction.
+	SignedTx *bitgo.SignedTx
+	// SendResult is a result returned by SendTransaction, SendCoins and SendMany.
+	SendResult *bitgo.SendResult
+	// TxInfos are transactions returned by Consolidate.
+	TxInfos []bitgo.TxInfo
+	// Err is an error returned by all methods.
+	Err error
+
+	ListFunc                    func(params *bitgo.WalletListParams) *bitgo.Pager[bitgo.Wallet]
+	GetFunc                     func(ctx context.Context, walletID string) (*bitgo.Wallet, error)
+	CreateAddressFunc           func(ctx context.Context, walletID string, chain bitgo.Chain) (*bitgo.Address, error)
+	AddressesFunc               func(walletID string, params *bitgo.AddressesParams) *bitgo.Pager[bitgo.Address]
+	AddressFunc                 func(ctx context.Context, walletID, address string) (*bitgo.Address, error)
+	TransactionsFunc            func(walletID string, params *bitgo.TransactionsParams) *bitgo.Pager[bitgo.Transaction]
+	TransactionFunc             func(ctx context.Context, walletID, txHash string) (*bitgo.Transaction, error)
+	TransactionBySequenceIDFunc func(ctx context.Context, walletID, sequenceID string) (*bitgo.Transaction, error)
+	UnspentsFunc                func(ctx context.Context, walletID string, params *bitgo.UnspentsParams, f func(*bitgo.UnspentList)) error
+	UnspentsPagerFunc           func(walletID string, params *bitgo.UnspentsParams) *bitgo.Pager[bitgo.Unspent]
+	CreateTransactionFunc       func(ctx context.Context, walletID string, params *bitgo.CreateTransactionParams) (*bitgo.UnsignedTx, error)
+	SignTransactionFunc         func(ctx context.Context, walletID string, params *bitgo.SignTransactionParams) (*bitgo.SignedTx, error)
+	SendTransactionFunc         func(ctx context.Context, params *bitgo.SendTransactionParams) (*bitgo.SendResult, error)
+	SendCoinsFunc               func(ctx context.Context, walletID string, params *bitgo.SendCoinsParams) (*bitgo.SendResult, error)
+	SendManyFunc                func(ctx context.Context, walletID string, params *bitgo.SendManyParams) (*bitgo.SendResult, error)
+	ConsolidateFunc             func(ctx context.Context, walletID string, bodyParams *bitgo.WalletConsolidateParams) ([]bitgo.TxInfo, error)
+}
+
+// List returns a Pager over WalletLists.
+func (m *WalletService) List(params *bitgo.WalletListParams) *bitgo.Pager[bitgo.Wallet] {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return listPager(m.WalletLists, m.Err, func(list *bitgo.WalletList) *bitgo.Page[bitgo.Wallet] {
+		return &bitgo.Page[bitgo.Wallet]{ListMeta: list.ListMeta, Items: list.Wallets}
+	})
+}
+
+// Get returns Wallet.
+func (m *WalletService) Get(ctx context.Context, walletID string) (*bitgo.Wallet, error) {
+	m.record("Get", walletID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, walletID)
+	}
+	return m.Wallet, m.Err
+}
+
+// CreateAddress returns AddressInfo.
+func (m *WalletService) CreateAddress(ctx context.Context, walletID string, chain bitgo.Chain) (*bitgo.Address, error) {
+	m.record("CreateAddress", walletID, chain)
+	if m.CreateAddressFunc != nil {
+		return m.CreateAddressFunc(ctx, walletID, chain)
+	}
+	return m.AddressInfo, m.Err
+}
+
+// Addresses returns a Pager over AddressLists.
+func (m *WalletService) Addresses(walletID string, params *bitgo.AddressesParams) *bitgo.Pager[bitgo.Address] {
+	m.record("Addresses", walletID, params)
+	if m.AddressesFunc != nil {
+		return m.AddressesFunc(walletID, params)
+	}
+	return listPager(m.AddressLists, m.Err, func(list *bitgo.AddressList) *bitgo.Page[bitgo.Address] {
+		return &bitgo.Page[bitgo.Address]{ListMeta: list.ListMeta, Items: list.Addresses}
+	})
+}
+
+// Address returns AddressInfo.
+func (m *WalletService) Address(ctx context.Context, walletID, address string) (*bitgo.Address, error) {
+	m.record("Address", walletID, address)
+	if m.AddressFunc != nil {
+		return m.AddressFunc(ctx, walletID, address)
+	}
+	return m.AddressInfo, m.Err
+}
+
+// Transactions returns a Pager over TransactionLists.
+func (m *WalletService) Transactions(walletID string, params *bitgo.TransactionsParams) *bitgo.Pager[bitgo.Transaction] {
+	m.record("Transactions", walletID, params)
+	if m.TransactionsFunc != nil {
+		return m.TransactionsFunc(walletID, params)
+	}
+	return listPager(m.TransactionLists, m.Err, func(list *bitgo.TransactionList) *bitgo.Page[bitgo.Transaction] {
+		return &bitgo.Page[bitgo.Transaction]{ListMeta: list.ListMeta, Items: list.Transactions}
+	})
+}
+
+// Transaction returns TransactionInfo.
+func (m *WalletService) Transaction(ctx context.Context, walletID, txHash string) (*bitgo.Transaction, error) {
+	m.record("Transaction", walletID, txHash)
+	if m.TransactionFunc != nil {
+		return m.TransactionFunc(ctx, walletID, txHash)
+	}
+	return m.TransactionInfo, m.Err
+}
+
+// TransactionBySequenceID returns TransactionInfo.
+func (m *WalletService) TransactionBySequenceID(ctx context.Context, walletID, sequenceID string) (*bitgo.Transaction, error) {
+	m.record("TransactionBySequenceID", walletID, sequenceID)
+	if m.TransactionBySequenceIDFunc != nil {
+		return m.TransactionBySequenceIDFunc(ctx, walletID, sequenceID)
+	}
+	return m.TransactionInfo, m.Err
+}
+
+// Unspents invokes f for each of UnspentLists.
+func (m *WalletService) Unspents(ctx context.Context, walletID string, params *bitgo.UnspentsParams, f func(*bitgo.UnspentList)) error {
+	m.record("Unspents", walletID, params)
+	if m.UnspentsFunc != nil {
+		return m.UnspentsFunc(ctx, walletID, params, f)
+	}
+	if m.Err != nil {
+		return m.Err
+	}
+	for _, list := range m.UnspentLists {
+		f(list)
+	}
+	return nil
+}
+
+// UnspentsPager returns a Pager over UnspentLists.
+func (m *WalletService) UnspentsPager(walletID string, params *bitgo.UnspentsParams) *bitgo.Pager[bitgo.Unspent] {
+	m.record("UnspentsPager", walletID, params)
+	if m.UnspentsPagerFunc != nil {
+		return m.UnspentsPagerFunc(walletID, params)
+	}
+	return listPager(m.UnspentLists, m.Err, func(list *bitgo.UnspentList) *bitgo.Page[bitgo.Unspent] {
+		return &bitgo.Page[bitgo.Unspent]{ListMeta: list.ListMeta, Items: list.Unspents}
+	})
+}
+
+// CreateTransaction returns UnsignedTx.
+func (m *WalletService) CreateTransaction(ctx context.Context, walletID string, params *bitgo.CreateTransactionParams) (*bitgo.UnsignedTx, error) {
+	m.record("CreateTransaction", walletID, params)
+	if m.CreateTransactionFunc != nil {
+		return m.CreateTransactionFunc(ctx, walletID, params)
+	}
+	return m.UnsignedTx, m.Err
+}
+
+// SignTransaction returns SignedTx.
+func (m *WalletService) SignTransaction(ctx context.Context, walletID string, params *bitgo.SignTransactionParams) (*bitgo.SignedTx, error) {
+	m.record("SignTransaction", walletID, params)
+	if m.SignTransactionFunc != nil {
+		return m.SignTransactionFunc(ctx, walletID, params)
+	}
+	return m.SignedTx, m.Err
+}
+
+// SendTransaction returns SendResult.
+func (m *WalletService) SendTransaction(ctx context.Context, params *bitgo.SendTransactionParams) (*bitgo.SendResult, error) {
+	m.record("SendTransaction", params)
+	if m.SendTransactionFunc != nil {
+		return m.SendTransactionFunc(ctx, params)
+	}
+	return m.SendResult, m.Err
+}
+
+// SendCoins returns SendResult.
+func (m *WalletService) SendCoins(ctx context.Context, walletID string, params *bitgo.SendCoinsParams) (*bitgo.SendResult, error) {
+	m.record("SendCoins", walletID, params)
+	if m.SendCoinsFunc != nil {
+		return m.SendCoinsFunc(ctx, walletID, params)
+	}
+	return m.SendResult, m.Err
+}
+
+// SendMany returns SendResult.
+func (m *WalletService) SendMany(ctx context.Context, walletID string, params *bitgo.SendManyParams) (*bitgo.SendResult, error) {
+	m.record("SendMany", walletID, params)
+	if m.SendManyFunc != nil {
+		return m.SendManyFunc(ctx, walletID, params)
+	}
+	return m.SendResult, m.Err
+}
+
+// Consolidate returns TxInfos.
+func (m *WalletService) Consolidate(ctx context.Context, walletID string, bodyParams *bitgo.WalletConsolidateParams) ([]bitgo.TxInfo, error) {
+	m.record("Consolidate", walletID, bodyParams)
+	if m.ConsolidateFunc != nil {
+		return m.ConsolidateFunc(ctx, walletID, bodyParams)
+	}
+	return m.TxInfos, m.Err
+}
+
+// listPager returns a Pager which serves canned lists as pages by their start index.
+// The error is returned instead of the first page if it's not nil.
+func listPager[L any, T any](lists []L, err error, page func(L) *bitgo.Page[T]) *bitgo.Pager[T] {
+	return bitgo.NewPager(0, func(ctx context.Context, skip int) (*bitgo.Page[T], error) {
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range lists {
+			if p := page(l); p.Start == skip {
+				return p, nil
+			}
+		}
+		return &bitgo.Page[T]{ListMeta: bitgo.ListMeta{Start: skip}}, nil
+	})
+}
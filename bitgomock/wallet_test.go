@@ -0,0 +1,66 @@
+package bitgomock_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+	"github.com/marselester/bitgo-v1/bitgomock"
+)
+
+func TestWalletServiceCannedResponses(t *testing.T) {
+	m := &bitgomock.WalletService{
+		UnspentLists: []*bitgo.UnspentList{
+			{ListMeta: bitgo.ListMeta{Start: 0, Count: 1, Total: 2}, Unspents: []bitgo.Unspent{{Value: 1}}},
+			{ListMeta: bitgo.ListMeta{Start: 1, Count: 1, Total: 2}, Unspents: []bitgo.Unspent{{Value: 2}}},
+		},
+		TxInfos: []bitgo.TxInfo{{TxID: "abc"}},
+	}
+	c := bitgo.NewClient()
+	c.Wallet = m
+
+	var got []bitgo.Amount
+	for utxo, err := range c.Wallet.UnspentsPager("wallet", nil).All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, utxo.Value)
+	}
+	if want := []bitgo.Amount{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	params := &bitgo.WalletConsolidateParams{WalletPassphrase: "root"}
+	tt, err := c.Wallet.Consolidate(context.Background(), "wallet", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tt) != 1 || tt[0].TxID != "abc" {
+		t.Errorf("unexpected transactions %#v", tt)
+	}
+
+	want := []bitgomock.Call{
+		{Method: "UnspentsPager", Args: []interface{}{"wallet", (*bitgo.UnspentsParams)(nil)}},
+		{Method: "Consolidate", Args: []interface{}{"wallet", params}},
+	}
+	if calls := m.Calls(); !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected calls %#v, got %#v", want, calls)
+	}
+}
+
+func TestWalletServiceFunc(t *testing.T) {
+	want := errors.New("boom")
+	m := &bitgomock.WalletService{
+		UnspentsFunc: func(ctx context.Context, walletID string, params *bitgo.UnspentsParams, f func(*bitgo.UnspentList)) error {
+			return want
+		},
+	}
+	if err := m.Unspents(context.Background(), "wallet", nil, nil); err != want {
+		t.Errorf("expected %v, got %v", want, err)
+	}
+	if calls := m.CallsTo("Unspents"); len(calls) != 1 {
+		t.Errorf("expected one Unspents call, got %#v", calls)
+	}
+}
@@ -0,0 +1,124 @@
+package bitgotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// defaultAccessTokenDuration is how long an access token is valid by default.
+const defaultAccessTokenDuration = 10 * 365 * 24 * time.Hour
+
+// createAccessToken creates a long-lived access token of the session's user if OTP is valid.
+// The token can be used as a bearer token right away.
+func (s *Server) createAccessToken(w http.ResponseWriter, r *http.Request) {
+	var p struct {
+		Label        string        `json:"label"`
+		OTP          string        `json:"otp"`
+		Duration     int           `json:"duration"`
+		Scope        []bitgo.Scope `json:"scope"`
+		IPRestrict   []string      `json:"ipRestrict"`
+		TxValueLimit bitgo.Amount  `json:"txValueLimit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if p.Label == "" || len(p.Scope) == 0 || p.Duration < 0 || p.TxValueLimit < 0 {
+		s.writeError(w, http.StatusBadRequest, "label and scope are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.authenticate(r)
+	if sess == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	switch p.OTP {
+	case OTP:
+	case "":
+		s.writeNeeds(w, "needsOTP", "needs otp")
+		return
+	default:
+		s.writeError(w, http.StatusUnauthorized, "incorrect otp")
+		return
+	}
+
+	d := defaultAccessTokenDuration
+	if p.Duration > 0 {
+		d = time.Duration(p.Duration) * time.Second
+	}
+	now := time.Now().UTC()
+	t := bitgo.AccessToken{
+		ID:           s.objectID(),
+		Token:        s.sessionToken(),
+		Label:        p.Label,
+		User:         sess.user.ID,
+		Client:       "bitgo",
+		Scope:        p.Scope,
+		Created:      now,
+		Expires:      now.Add(d),
+		Origin:       "test.bitgo.com",
+		IPRestrict:   p.IPRestrict,
+		TxValueLimit: p.TxValueLimit,
+	}
+	s.sessions[t.Token] = &session{
+		user:        sess.user,
+		created:     t.Created,
+		expires:     t.Expires,
+		accessToken: &t,
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+// listAccessTokens lists access tokens of the session's user without the tokens themselves.
+func (s *Server) listAccessTokens(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.authenticate(r)
+	if sess == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	tokens := []bitgo.AccessToken{}
+	for _, other := range s.sessions {
+		if other.user != sess.user || other.accessToken == nil {
+			continue
+		}
+		t := *other.accessToken
+		t.Token = ""
+		tokens = append(tokens, t)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].ID < tokens[j].ID })
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"accessTokens": tokens,
+	})
+}
+
+// revokeAccessToken deletes the access token of the session's user by its ID.
+func (s *Server) revokeAccessToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.authenticate(r)
+	if sess == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id := r.PathValue("id")
+	for token, other := range s.sessions {
+		if other.user == sess.user && other.accessToken != nil && other.accessToken.ID == id {
+			delete(s.sessions, token)
+			writeJSON(w, http.StatusOK, other.accessToken)
+			return
+		}
+	}
+	s.writeError(w, http.StatusNotFound, "access token not found")
+}
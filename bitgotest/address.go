@@ -0,0 +1,150 @@
+package bitgotest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// createAddress creates a new address at the next index of the chain.
+func (s *Server) createAddress(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.PathValue("chain"))
+	chain := bitgo.Chain(n)
+	if err != nil || !validChain(chain) {
+		s.writeError(w, http.StatusBadRequest, "invalid chain")
+		return
+	}
+
+	s.mu.Lock()
+	wlt := s.wallets[r.PathValue("id")]
+	var a bitgo.Address
+	if wlt != nil {
+		var index int
+		for _, a := range wlt.addresses {
+			if a.Chain == chain {
+				index++
+			}
+		}
+		a = bitgo.Address{
+			Address: s.address(),
+			Chain:   chain,
+			Index:   index,
+			Path:    fmt.Sprintf("/%d/%d", chain, index),
+			Wallet:  wlt.id,
+		}
+		wlt.addresses = append(wlt.addresses, a)
+	}
+	s.mu.Unlock()
+
+	if wlt == nil {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// listAddresses lists wallet addresses filtered by chain and paginated with skip and limit.
+func (s *Server) listAddresses(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	skip, err := intParam(q.Get("skip"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid skip")
+		return
+	}
+	limit, err := intParam(q.Get("limit"))
+	if err != nil || limit > maxAddressesLimit {
+		s.writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	if limit == 0 {
+		limit = defaultAddressesLimit
+	}
+	chain := -1
+	if q.Get("chain") != "" {
+		if chain, err = intParam(q.Get("chain")); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid chain")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	wlt := s.wallets[r.PathValue("id")]
+	var addresses []bitgo.Address
+	if wlt != nil {
+		for _, a := range wlt.addresses {
+			if chain == -1 || a.Chain == bitgo.Chain(chain) {
+				addresses = append(addresses, wlt.addressInfo(a))
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if wlt == nil {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	list := bitgo.AddressList{
+		ListMeta: bitgo.ListMeta{
+			Start: skip,
+			Total: len(addresses),
+		},
+		Addresses: []bitgo.Address{},
+	}
+	if skip < len(addresses) {
+		end := skip + limit
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+		list.Addresses = addresses[skip:end]
+	}
+	list.Count = len(list.Addresses)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// getAddress returns the wallet address with its balance calculated from unspents.
+func (s *Server) getAddress(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	wlt := s.wallets[r.PathValue("id")]
+	var a bitgo.Address
+	var ok bool
+	if wlt != nil {
+		for _, a = range wlt.addresses {
+			if ok = a.Address == r.PathValue("address"); ok {
+				a = wlt.addressInfo(a)
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "address not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// addressInfo returns the address with its balance and totals. The caller must hold the lock.
+func (wlt *wallet) addressInfo(a bitgo.Address) bitgo.Address {
+	for _, u := range wlt.unspents {
+		if u.Address == a.Address {
+			a.Balance += u.Value
+			a.TxCount++
+		}
+	}
+	a.Received = a.Balance
+	return a
+}
+
+// validChain returns true if the chain is one of the known address chains.
+func validChain(c bitgo.Chain) bool {
+	switch c {
+	case bitgo.ChainP2SH, bitgo.ChainP2SHChange,
+		bitgo.ChainP2SHP2WSH, bitgo.ChainP2SHP2WSHChange,
+		bitgo.ChainP2WSH, bitgo.ChainP2WSHChange:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package bitgotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// spendingLimitPolicy is the ID of the policy rule set by SetSpendingLimit.
+const spendingLimitPolicy = "spending-limit"
+
+// approval is a pending approval of a transaction along with its params
+// used to send the transaction once it's approved.
+type approval struct {
+	bitgo.PendingApproval
+	params *bitgo.SendManyParams
+}
+
+// pendingApproval creates a pending approval of the transaction. The caller must hold the lock.
+func (s *Server) pendingApproval(wlt *wallet, p *bitgo.SendManyParams) bitgo.PendingApproval {
+	var total bitgo.Amount
+	for _, rcpt := range p.Recipients {
+		total += rcpt.Amount
+	}
+	a := approval{
+		PendingApproval: bitgo.PendingApproval{
+			ID:                s.objectID(),
+			WalletID:          wlt.id,
+			CreateDate:        time.Now().UTC(),
+			State:             bitgo.ApprovalStatePending,
+			ApprovalsRequired: 1,
+			Info: bitgo.ApprovalInfo{
+				Type: bitgo.ApprovalTypeTransactionRequest,
+				TransactionRequest: &bitgo.TransactionRequest{
+					RequestedAmount: total,
+					SourceWallet:    wlt.id,
+					Recipients:      p.Recipients,
+					Message:         p.Message,
+					SequenceID:      p.SequenceID,
+					TriggeredPolicy: spendingLimitPolicy,
+				},
+			},
+		},
+		params: p,
+	}
+	wlt.approvals = append(wlt.approvals, &a)
+	return a.PendingApproval
+}
+
+// findApproval returns the approval by its ID. The caller must hold the lock.
+func (s *Server) findApproval(id string) (*wallet, *approval) {
+	for _, wlt := range s.wallets {
+		for _, a := range wlt.approvals {
+			if a.ID == id {
+				return wlt, a
+			}
+		}
+	}
+	return nil, nil
+}
+
+// listApprovals lists pending approvals of a wallet or an enterprise
+// specified by walletId or enterprise query param.
+func (s *Server) listApprovals(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	walletID, enterprise := q.Get("walletId"), q.Get("enterprise")
+	if (walletID == "") == (enterprise == "") {
+		s.writeError(w, http.StatusBadRequest, "either walletId or enterprise is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var wallets []*wallet
+	if walletID != "" {
+		wlt := s.wallets[walletID]
+		if wlt == nil {
+			s.writeError(w, http.StatusNotFound, "wallet not found")
+			return
+		}
+		wallets = append(wallets, wlt)
+	} else {
+		for _, wlt := range s.wallets {
+			wallets = append(wallets, wlt)
+		}
+		sort.Slice(wallets, func(i, j int) bool { return wallets[i].id < wallets[j].id })
+	}
+
+	approvals := []bitgo.PendingApproval{}
+	for _, wlt := range wallets {
+		for _, a := range wlt.approvals {
+			if a.State != bitgo.ApprovalStatePending || (enterprise != "" && a.Enterprise != enterprise) {
+				continue
+			}
+			approvals = append(approvals, a.PendingApproval)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pendingApprovals": approvals,
+	})
+}
+
+// getApproval returns the approval by its ID.
+func (s *Server) getApproval(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, a := s.findApproval(r.PathValue("id"))
+	if a == nil {
+		s.writeError(w, http.StatusNotFound, "pending approval not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, a.PendingApproval)
+}
+
+// updateApproval approves or rejects the approval if OTP is valid. An approved transaction is sent.
+func (s *Server) updateApproval(w http.ResponseWriter, r *http.Request) {
+	var p struct {
+		State string `json:"state"`
+		OTP   string `json:"otp"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if p.State != bitgo.ApprovalStateApproved && p.State != bitgo.ApprovalStateRejected {
+		s.writeError(w, http.StatusBadRequest, "state must be approved or rejected")
+		return
+	}
+	switch p.OTP {
+	case OTP:
+	case "":
+		s.writeNeeds(w, "needsOTP", "needs otp")
+		return
+	default:
+		s.writeError(w, http.StatusUnauthorized, "incorrect otp")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wlt, a := s.findApproval(r.PathValue("id"))
+	if a == nil {
+		s.writeError(w, http.StatusNotFound, "pending approval not found")
+		return
+	}
+	if a.State != bitgo.ApprovalStatePending {
+		s.writeError(w, http.StatusBadRequest, "pending approval is already resolved")
+		return
+	}
+
+	if p.State == bitgo.ApprovalStateApproved {
+		tx, err := s.buildTx(wlt, a.params.Recipients, a.params.FeeRate, a.params.MinConfirms, a.params.EnforceMinConfirmsForChange)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.sendTx(wlt, tx, a.params.SequenceID, a.params.Message, a.params.Instant)
+		wlt.transactions[len(wlt.transactions)-1].PendingApproval = a.ID
+		a.Info.TransactionRequest.Fee = tx.fee
+	}
+	a.State = p.State
+	writeJSON(w, http.StatusOK, a.PendingApproval)
+}
@@ -0,0 +1,83 @@
+package bitgotest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serveHMAC serves the request signed by bitgo.HMACAuthenticator with a session or access token
+// issued by the server, and signs the response with the same token.
+func (s *Server) serveHMAC(w http.ResponseWriter, r *http.Request, h http.HandlerFunc) {
+	hash, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token := s.tokenByHash(hash)
+	if token == "" {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	subject := strings.Join([]string{
+		r.Method,
+		r.Header.Get("Auth-Timestamp"),
+		r.Header.Get("BitGo-Auth-Version"),
+		r.URL.RequestURI(),
+		string(body),
+	}, "|")
+	if !hmac.Equal([]byte(r.Header.Get("HMAC")), []byte(signHMAC(token, subject))) {
+		s.writeError(w, http.StatusUnauthorized, "invalid hmac")
+		return
+	}
+	// Handlers authenticate requests by the bearer token.
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	rec := httptest.NewRecorder()
+	h(rec, r)
+	ts := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	subject = strings.Join([]string{
+		r.Method,
+		ts,
+		r.URL.RequestURI(),
+		strconv.Itoa(rec.Code),
+		rec.Body.String(),
+	}, "|")
+	for k, vv := range rec.Header() {
+		w.Header()[k] = vv
+	}
+	w.Header().Set("Timestamp", ts)
+	w.Header().Set("HMAC", signHMAC(token, subject))
+	w.WriteHeader(rec.Code)
+	w.Write(rec.Body.Bytes())
+}
+
+// tokenByHash returns the session or access token by its SHA-256 hash in hex format.
+func (s *Server) tokenByHash(hash string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for token := range s.sessions {
+		h := sha256.Sum256([]byte(token))
+		if hex.EncodeToString(h[:]) == hash {
+			return token
+		}
+	}
+	return ""
+}
+
+// signHMAC returns HMAC-SHA256 of the subject keyed with the token in hex format.
+func signHMAC(token, subject string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(subject))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,194 @@
+package bitgotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// Defaults of the coin-scoped API v2.
+const (
+	defaultCoinUnspentsLimit = 100
+	maxCoinUnspentsLimit     = 500
+)
+
+// getCoinWallet returns the wallet in API v2 format. Wallets are shared by all coins.
+func (s *Server) getCoinWallet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	wlt := s.wallets[r.PathValue("id")]
+	var info bitgo.Wallet
+	if wlt != nil {
+		info = wlt.info()
+	}
+	s.mu.Unlock()
+
+	if wlt == nil {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":               info.ID,
+		"coin":             r.PathValue("coin"),
+		"label":            info.Label,
+		"type":             "hot",
+		"deleted":          false,
+		"balance":          info.Balance,
+		"confirmedBalance": info.ConfirmedBalance,
+		"spendableBalance": info.SpendableBalance,
+		"admin":            info.Admin,
+		"pendingApprovals": info.PendingApprovals,
+		"users": []map[string]interface{}{
+			{"user": "fake", "permissions": []string{"admin", "spend", "view"}},
+		},
+	})
+}
+
+// coinUnspents lists wallet unspents in API v2 format filtered by minConfirms, minValue and segwit
+// query params and paginated with prevId and limit.
+func (s *Server) coinUnspents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var p struct {
+		minConfirms, minValue, limit int64
+	}
+	for name, v := range map[string]*int64{
+		"minConfirms": &p.minConfirms,
+		"minValue":    &p.minValue,
+		"limit":       &p.limit,
+	} {
+		if q.Get(name) == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(q.Get(name), 10, 64)
+		if err != nil || n < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid "+name)
+			return
+		}
+		*v = n
+	}
+	if p.limit == 0 {
+		p.limit = defaultCoinUnspentsLimit
+	}
+	if p.limit > maxCoinUnspentsLimit {
+		s.writeError(w, http.StatusBadRequest, "limit must be at most 500")
+		return
+	}
+	segwit := q.Get("segwit") != "false"
+	prevID := q.Get("prevId")
+
+	unspents, ok := s.walletUnspents(r.PathValue("id"))
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	// The page starts after the unspent with prevId.
+	if prevID != "" {
+		for i, u := range unspents {
+			if u.OutPoint() == prevID {
+				unspents = unspents[i+1:]
+				break
+			}
+		}
+	}
+	list := []map[string]interface{}{}
+	var next string
+	for _, u := range unspents {
+		switch {
+		case int64(u.Confirmations) < p.minConfirms:
+			continue
+		case int64(u.Value) < p.minValue:
+			continue
+		case !segwit && isSegwit(u):
+			continue
+		}
+		if int64(len(list)) == p.limit {
+			next = list[len(list)-1]["id"].(string)
+			break
+		}
+		chain, index := chainIndex(u.ChainPath)
+		list = append(list, map[string]interface{}{
+			"id":            u.OutPoint(),
+			"address":       u.Address,
+			"value":         u.Value,
+			"valueString":   strconv.FormatInt(int64(u.Value), 10),
+			"blockHeight":   u.BlockHeight,
+			"date":          u.Date,
+			"wallet":        u.Wallet,
+			"chain":         chain,
+			"index":         index,
+			"redeemScript":  u.RedeemScript,
+			"witnessScript": u.WitnessScript,
+			"isSegwit":      isSegwit(u),
+		})
+	}
+
+	v := map[string]interface{}{
+		"coin":     r.PathValue("coin"),
+		"unspents": list,
+	}
+	if next != "" {
+		v["nextBatchPrevId"] = next
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// chainIndex returns the chain and the index of the BIP32 path relative to the wallet, e.g., "/1/7".
+func chainIndex(chainPath string) (chain, index int) {
+	parts := strings.Split(strings.TrimPrefix(chainPath, "/"), "/")
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	chain, _ = strconv.Atoi(parts[0])
+	index, _ = strconv.Atoi(parts[1])
+	return chain, index
+}
+
+// coinConsolidate consolidates wallet unspents selected by minConfirms, minValue and maxValue
+// into numUnspentsToMake new unspents with a single transaction.
+func (s *Server) coinConsolidate(w http.ResponseWriter, r *http.Request) {
+	if !s.checkUnlock(w, r) {
+		return
+	}
+	var p struct {
+		WalletPassphrase  string        `json:"walletPassphrase"`
+		NumUnspentsToMake int           `json:"numUnspentsToMake"`
+		Limit             int           `json:"limit"`
+		MinConfirms       int           `json:"minConfirms"`
+		MinValue          bitgo.Amount  `json:"minValue"`
+		MaxValue          bitgo.Amount  `json:"maxValue"`
+		FeeRate           bitgo.FeeRate `json:"feeRate"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	tt, ok := s.consolidateUnspents(w, r.PathValue("id"), &bitgo.WalletConsolidateParams{
+		WalletPassphrase:  p.WalletPassphrase,
+		NumUnspentsToMake: p.NumUnspentsToMake,
+		Limit:             p.Limit,
+		MinConfirms:       p.MinConfirms,
+		MinValue:          p.MinValue,
+		MaxValue:          p.MaxValue,
+		FeeRate:           p.FeeRate,
+		MaxIter:           1,
+	})
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"txid":   tt[0].TxID,
+		"tx":     tt[0].Tx,
+		"status": "signed",
+		"transfer": map[string]interface{}{
+			"coin":      r.PathValue("coin"),
+			"txid":      tt[0].TxID,
+			"fee":       tt[0].Fee,
+			"feeString": strconv.FormatInt(int64(tt[0].Fee), 10),
+		},
+	})
+}
@@ -0,0 +1,84 @@
+package bitgotest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// defaultFeeBlocks is a number of blocks the fee is estimated for when numBlocks isn't set.
+const defaultFeeBlocks = 2
+
+// defaultFeeRates are fee rates by block target returned by the fee estimate endpoint.
+var defaultFeeRates = map[int]bitgo.FeeRate{
+	1:  20000,
+	2:  15000,
+	6:  10000,
+	12: 5000,
+}
+
+// SetFeeRates sets fee rates by the number of blocks to confirm within
+// which are returned by the fee estimate endpoint. Nil rates restore the defaults.
+func (s *Server) SetFeeRates(byBlockTarget map[int]bitgo.FeeRate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if byBlockTarget == nil {
+		s.feeRates = nil
+		return
+	}
+	s.feeRates = make(map[int]bitgo.FeeRate, len(byBlockTarget))
+	for n, r := range byBlockTarget {
+		s.feeRates[n] = r
+	}
+}
+
+// estimateFee returns fee rates by block target and the rate for numBlocks query param.
+// The rate of the closest block target at or below numBlocks is recommended,
+// or the fastest one if numBlocks is below all targets.
+func (s *Server) estimateFee(w http.ResponseWriter, r *http.Request) {
+	numBlocks, err := intParam(r.URL.Query().Get("numBlocks"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if numBlocks == 0 {
+		numBlocks = defaultFeeBlocks
+	}
+
+	s.mu.Lock()
+	rates := s.feeRates
+	if rates == nil {
+		rates = defaultFeeRates
+	}
+	targets := make([]int, 0, len(rates))
+	byTarget := make(map[int]bitgo.FeeRate, len(rates))
+	for n, r := range rates {
+		targets = append(targets, n)
+		byTarget[n] = r
+	}
+	s.mu.Unlock()
+
+	if len(targets) == 0 {
+		s.writeError(w, http.StatusServiceUnavailable, "fee estimates are unavailable")
+		return
+	}
+	sort.Ints(targets)
+	rate := byTarget[targets[0]]
+	for _, n := range targets {
+		if n > numBlocks {
+			break
+		}
+		rate = byTarget[n]
+	}
+
+	writeJSON(w, http.StatusOK, bitgo.FeeEstimate{
+		FeePerKB:         rate,
+		CPFPFeePerKB:     rate,
+		NumBlocks:        numBlocks,
+		Confidence:       80,
+		Multiplier:       1,
+		FeeByBlockTarget: byTarget,
+	})
+}
@@ -0,0 +1,259 @@
+package bitgotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// Mode defines whether Recorder talks to a real server or replays a cassette.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette without network access.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to a real server and saves them in the cassette.
+	ModeRecord
+)
+
+// Cassette is a list of recorded HTTP interactions stored as a JSON file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+// Secrets in bodies are redacted with bitgo.Redact, and request headers
+// such as Authorization aren't recorded at all.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request recorded in the cassette.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response recorded in the cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// skippedHeaders are response headers which are not recorded.
+var skippedHeaders = []string{"Set-Cookie", "Date", "Content-Length"}
+
+// Recorder is an http.RoundTripper which records interactions with BitGo API to a cassette file
+// or replays them from it. It's safe for concurrent use.
+//
+//	mode := bitgotest.ModeReplay
+//	if os.Getenv("BITGO_RECORD") != "" {
+//		mode = bitgotest.ModeRecord
+//	}
+//	rec, err := bitgotest.NewRecorder("testdata/unspents.cassette.json", mode)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Save()
+//	c := bitgo.NewClient(bitgo.WithHTTPClient(rec.Client()), ...)
+type Recorder struct {
+	// Transport sends requests in record mode. It defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu       sync.Mutex
+	cassette Cassette
+	// played marks interactions which were already replayed.
+	played []bool
+}
+
+// NewRecorder returns a Recorder of the cassette file.
+// In replay mode the cassette must exist.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := Recorder{
+		path: path,
+		mode: mode,
+	}
+	if mode == ModeRecord {
+		return &r, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("bitgotest: failed to read cassette: %w", err)
+	}
+	if err = json.Unmarshal(b, &r.cassette); err != nil {
+		return nil, fmt.Errorf("bitgotest: failed to decode cassette %s: %w", path, err)
+	}
+	r.played = make([]bool, len(r.cassette.Interactions))
+	return &r, nil
+}
+
+// Client returns an HTTP client which uses the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays the request depending on the mode.
+// In replay mode it returns an error if no recorded interaction matches the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, req, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	t := r.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	resp, err := t.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	for _, h := range skippedHeaders {
+		header.Del(h)
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       bitgo.Redact(body),
+		},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay returns the response of the first interaction which matches the request and wasn't played yet.
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		if r.played[i] || in.Request != recorded {
+			continue
+		}
+		r.played[i] = true
+		body := []byte(in.Response.Body)
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("bitgotest: no unplayed interaction in cassette %s matches %s %s?%s with body %q",
+		r.path, recorded.Method, recorded.Path, recorded.Query, recorded.Body)
+}
+
+// Unplayed returns recorded interactions which weren't replayed,
+// so a test can check that all expected requests were made.
+func (r *Recorder) Unplayed() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ii []Interaction
+	for i, in := range r.cassette.Interactions {
+		if !r.played[i] {
+			ii = append(ii, in)
+		}
+	}
+	return ii
+}
+
+// Save writes the recorded interactions to the cassette file creating its directory if needed.
+// It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("bitgotest: failed to encode cassette: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("bitgotest: failed to create cassette dir: %w", err)
+	}
+	if err = ioutil.WriteFile(r.path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("bitgotest: failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// recordRequest returns the request as it's stored in the cassette.
+// A request whose body can't be read again is cloned with a copy of the body,
+// so the caller's request isn't modified as http.RoundTripper requires.
+// The query is normalized and secrets in the body are redacted,
+// so a replayed request matches the recorded one even if it carries real credentials.
+func recordRequest(req *http.Request) (RecordedRequest, *http.Request, error) {
+	var body []byte
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+	case req.GetBody != nil:
+		rc, err := req.GetBody()
+		if err != nil {
+			return RecordedRequest{}, nil, fmt.Errorf("bitgotest: failed to read request body: %w", err)
+		}
+		body, err = ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return RecordedRequest{}, nil, fmt.Errorf("bitgotest: failed to read request body: %w", err)
+		}
+	default:
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return RecordedRequest{}, nil, fmt.Errorf("bitgotest: failed to read request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Body:   bitgo.Redact(body),
+	}, req, nil
+}
@@ -0,0 +1,113 @@
+package bitgotest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+	"github.com/marselester/bitgo-v1/bitgotest"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	cassette := filepath.Join(t.TempDir(), "testdata", "consolidate.json")
+
+	rec, err := bitgotest.NewRecorder(cassette, bitgotest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("secret-token"),
+		bitgo.WithHTTPClient(rec.Client()),
+	)
+	params := bitgo.WalletConsolidateParams{
+		WalletPassphrase: "root",
+		MaxValue:         40000,
+	}
+	want, err := c.Wallet.Consolidate(context.Background(), walletID, &params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-token", `\"root\"`} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %s:\n%s", secret, b)
+		}
+	}
+
+	// The server is not needed to replay the cassette.
+	srv.Close()
+	if rec, err = bitgotest.NewRecorder(cassette, bitgotest.ModeReplay); err != nil {
+		t.Fatal(err)
+	}
+	c = bitgo.NewClient(
+		bitgo.WithBaseURL("http://bitgo.invalid"),
+		bitgo.WithHTTPClient(rec.Client()),
+	)
+	got, err := c.Wallet.Consolidate(context.Background(), walletID, &params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].TxID != want[0].TxID || got[0].Fee != want[0].Fee {
+		t.Errorf("expected %#v, got %#v", want, got)
+	}
+	if uu := rec.Unplayed(); len(uu) != 0 {
+		t.Errorf("expected all interactions played, got %#v", uu)
+	}
+
+	// The interaction was already played.
+	_, err = c.Wallet.Consolidate(context.Background(), walletID, &params)
+	if err == nil || !strings.Contains(err.Error(), "no unplayed interaction") {
+		t.Errorf("expected unmatched request error, got %v", err)
+	}
+
+	params.MaxValue = 50000
+	_, err = c.Wallet.Consolidate(context.Background(), walletID, &params)
+	if err == nil || !strings.Contains(err.Error(), `PUT /api/v1/wallet/`+walletID+`/consolidateunspents`) {
+		t.Errorf("expected unmatched request error, got %v", err)
+	}
+}
+
+func TestRecorderKeepsRequest(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	rec, err := bitgotest.NewRecorder(filepath.Join(t.TempDir(), "request.json"), bitgotest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The body can't be read again because the request has no GetBody.
+	body := ioutil.NopCloser(strings.NewReader(`{"label":"payouts"}`))
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/wallet/"+walletID, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if req.Body != body {
+		t.Error("the caller's request must not be modified")
+	}
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	_, err := bitgotest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), bitgotest.ModeReplay)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
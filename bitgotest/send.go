@@ -0,0 +1,384 @@
+package bitgotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// Size estimates of a transaction used to calculate fees.
+const (
+	defaultSendFeeRate  = 10000
+	sendTxOverheadBytes = 10
+	sendTxInputBytes    = 296
+	sendTxOutputBytes   = 34
+)
+
+// SetSpendingLimit sets a policy of the wallet which requires approval of transactions
+// which send more than the limit. Zero limit removes the policy.
+func (s *Server) SetSpendingLimit(walletID string, limit bitgo.Amount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w := s.wallets[walletID]; w != nil {
+		w.spendingLimit = limit
+	}
+}
+
+// sendCoins sends coins to a single address.
+func (s *Server) sendCoins(w http.ResponseWriter, r *http.Request) {
+	var p bitgo.SendCoinsParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.send(w, r, &bitgo.SendManyParams{
+		Recipients:                  []bitgo.Recipient{{Address: p.Address, Amount: p.Amount}},
+		WalletPassphrase:            p.WalletPassphrase,
+		FeeRate:                     p.FeeRate,
+		MinConfirms:                 p.MinConfirms,
+		EnforceMinConfirmsForChange: p.EnforceMinConfirmsForChange,
+		SequenceID:                  p.SequenceID,
+		Message:                     p.Message,
+		Instant:                     p.Instant,
+		OTP:                         p.OTP,
+	})
+}
+
+// sendMany sends coins to multiple addresses.
+func (s *Server) sendMany(w http.ResponseWriter, r *http.Request) {
+	var p bitgo.SendManyParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.send(w, r, &p)
+}
+
+// send spends wallet unspents to pay the recipients. Transactions which exceed the wallet's
+// spending limit are not sent, instead a pending approval is created.
+func (s *Server) send(w http.ResponseWriter, r *http.Request, p *bitgo.SendManyParams) {
+	if !s.checkUnlock(w, r) {
+		return
+	}
+	if err := p.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if p.FeeRate == 0 {
+		p.FeeRate = defaultSendFeeRate
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wlt := s.wallets[r.PathValue("id")]
+	if wlt == nil {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	if p.WalletPassphrase != wlt.passphrase {
+		s.writeError(w, http.StatusBadRequest, "Unable to decrypt user keychain")
+		return
+	}
+	if p.SequenceID != "" {
+		for _, t := range wlt.transactions {
+			if t.SequenceID == p.SequenceID {
+				s.writeError(w, http.StatusBadRequest, "transaction with this sequenceId already exists")
+				return
+			}
+		}
+	}
+
+	var total bitgo.Amount
+	for _, rcpt := range p.Recipients {
+		total += rcpt.Amount
+	}
+	if wlt.spendingLimit > 0 && total > wlt.spendingLimit {
+		a := s.pendingApproval(wlt, p)
+		writeJSON(w, http.StatusAccepted, map[string]string{
+			"error":           "exceeds a spending limit",
+			"pendingApproval": a.ID,
+			"triggeredPolicy": spendingLimitPolicy,
+			"status":          bitgo.SendStatusPendingApproval,
+		})
+		return
+	}
+
+	tx, err := s.buildTx(wlt, p.Recipients, p.FeeRate, p.MinConfirms, p.EnforceMinConfirmsForChange)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	t := s.sendTx(wlt, tx, p.SequenceID, p.Message, p.Instant)
+	writeJSON(w, http.StatusOK, bitgo.SendResult{
+		Status:      "accepted",
+		TxID:        t.ID,
+		Tx:          t.Hex,
+		Fee:         t.Fee,
+		FeeRate:     p.FeeRate,
+		Instant:     t.Instant,
+		TravelInfos: []bitgo.TravelInfo{},
+	})
+}
+
+// fakeTx is a transaction built from wallet unspents.
+type fakeTx struct {
+	// wallet and hex are set when the transaction is built by createtransaction endpoint.
+	wallet     *wallet
+	hex        string
+	inputs     []bitgo.Unspent
+	recipients []bitgo.Recipient
+	change     bitgo.Unspent
+	fee        bitgo.Amount
+	feeRate    bitgo.FeeRate
+}
+
+// buildTx selects wallet unspents to pay the recipients and the fee.
+// Unspents with fewer than minConfirms confirmations are skipped unless they are change
+// and enforcement for change is turned off. The caller must hold the lock.
+func (s *Server) buildTx(wlt *wallet, recipients []bitgo.Recipient, feeRate bitgo.FeeRate, minConfirms int, enforceForChange *bool) (*fakeTx, error) {
+	tx := fakeTx{
+		recipients: recipients,
+		feeRate:    feeRate,
+	}
+	var total bitgo.Amount
+	for _, rcpt := range recipients {
+		total += rcpt.Amount
+	}
+
+	var sum bitgo.Amount
+	for _, u := range wlt.unspents {
+		unconfirmedChange := u.IsChange && enforceForChange != nil && !*enforceForChange
+		if u.Confirmations < minConfirms && !unconfirmedChange {
+			continue
+		}
+		tx.inputs = append(tx.inputs, u)
+		sum += u.Value
+		// The change output is always accounted for to keep the estimate simple.
+		size := sendTxOverheadBytes + sendTxInputBytes*len(tx.inputs) + sendTxOutputBytes*(len(recipients)+1)
+		tx.fee = bitgo.Amount(int64(feeRate) * int64(size) / 1000)
+		if sum >= total+tx.fee {
+			break
+		}
+	}
+	if sum < total+tx.fee {
+		return nil, fmt.Errorf("Insufficient funds: need %d, have %d", total+tx.fee, sum)
+	}
+
+	if change := sum - total - tx.fee; change > 0 {
+		tx.change = bitgo.Unspent{
+			Address:   s.address(),
+			Value:     change,
+			ChainPath: "/1/0",
+			IsChange:  true,
+			Wallet:    wlt.id,
+		}
+	}
+	return &tx, nil
+}
+
+// sendTx removes the transaction inputs from the wallet, adds its change as a new unspent
+// and records the transaction in the wallet's history. The caller must hold the lock.
+func (s *Server) sendTx(wlt *wallet, tx *fakeTx, sequenceID, message string, instant bool) bitgo.Transaction {
+	t := bitgo.Transaction{
+		ID:         s.txHash(),
+		Date:       time.Now().UTC(),
+		Fee:        tx.fee,
+		Pending:    true,
+		Instant:    instant,
+		Comment:    message,
+		SequenceID: sequenceID,
+	}
+	t.Hex = "01000000" + t.ID
+
+	spent := make(map[string]bool, len(tx.inputs))
+	for _, u := range tx.inputs {
+		spent[u.OutPoint()] = true
+		t.Inputs = append(t.Inputs, bitgo.TxInput{PreviousHash: u.TxHash, PreviousOutputIndex: u.TxOutputN})
+		t.Entries = append(t.Entries, bitgo.TxEntry{Account: u.Address, Value: -u.Value})
+	}
+	unspents := wlt.unspents[:0]
+	for _, u := range wlt.unspents {
+		if !spent[u.OutPoint()] {
+			unspents = append(unspents, u)
+		}
+	}
+	wlt.unspents = unspents
+
+	for i, rcpt := range tx.recipients {
+		t.Outputs = append(t.Outputs, bitgo.TxOutput{Vout: i, Value: rcpt.Amount, Account: rcpt.Address})
+		t.Entries = append(t.Entries, bitgo.TxEntry{Account: rcpt.Address, Value: rcpt.Amount})
+	}
+	if tx.change.Value > 0 {
+		u := tx.change
+		u.TxHash = t.ID
+		u.TxOutputN = len(t.Outputs)
+		u.Instant = instant
+		wlt.unspents = append(wlt.unspents, u)
+		t.Outputs = append(t.Outputs, bitgo.TxOutput{
+			Vout:    u.TxOutputN,
+			Value:   u.Value,
+			Account: u.Address,
+			IsMine:  true,
+			Chain:   bitgo.ChainP2SHChange,
+		})
+		t.Entries = append(t.Entries, bitgo.TxEntry{Account: u.Address, Value: u.Value})
+	}
+	wlt.transactions = append(wlt.transactions, t)
+	return t
+}
+
+// signedSuffix is appended to a transaction hex to mark it as signed.
+const signedSuffix = "00"
+
+// createTransaction builds an unsigned transaction and remembers it until it's sent.
+func (s *Server) createTransaction(w http.ResponseWriter, r *http.Request) {
+	var p bitgo.CreateTransactionParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := p.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if p.FeeRate == 0 {
+		p.FeeRate = defaultSendFeeRate
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wlt := s.wallets[r.PathValue("id")]
+	if wlt == nil {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	tx, err := s.buildTx(wlt, p.Recipients, p.FeeRate, p.MinConfirms, p.EnforceMinConfirmsForChange)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if p.ChangeAddress != "" {
+		tx.change.Address = p.ChangeAddress
+	}
+	tx.wallet = wlt
+	tx.hex = "01000000" + s.txHash()
+	s.unsigned[tx.hex] = tx
+
+	unsigned := bitgo.UnsignedTx{
+		TransactionHex:  tx.hex,
+		Unspents:        tx.inputs,
+		ChangeAddresses: []bitgo.ChangeAddress{},
+		Fee:             tx.fee,
+		FeeRate:         tx.feeRate,
+		EstimatedSize:   int(int64(tx.fee) * 1000 / int64(tx.feeRate)),
+		WalletID:        wlt.id,
+		TravelInfos:     []bitgo.TravelInfo{},
+	}
+	if tx.change.Value > 0 {
+		unsigned.ChangeAddresses = append(unsigned.ChangeAddresses, bitgo.ChangeAddress{
+			Address: tx.change.Address,
+			Path:    tx.change.ChainPath,
+			Amount:  tx.change.Value,
+		})
+	}
+	writeJSON(w, http.StatusOK, unsigned)
+}
+
+// signTransaction marks the unsigned transaction as signed.
+func (s *Server) signTransaction(w http.ResponseWriter, r *http.Request) {
+	var p bitgo.SignTransactionParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := p.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	tx := s.unsigned[p.TransactionHex]
+	s.mu.Unlock()
+
+	if tx == nil || tx.wallet.id != r.PathValue("id") {
+		s.writeError(w, http.StatusBadRequest, "unknown transaction")
+		return
+	}
+	writeJSON(w, http.StatusOK, bitgo.SignedTx{Tx: tx.hex + signedSuffix})
+}
+
+// sendTransaction sends the signed transaction unless it exceeds the wallet's spending limit
+// or its inputs were already spent.
+func (s *Server) sendTransaction(w http.ResponseWriter, r *http.Request) {
+	if !s.checkUnlock(w, r) {
+		return
+	}
+	var p bitgo.SendTransactionParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hex := strings.TrimSuffix(p.Tx, signedSuffix)
+	tx := s.unsigned[hex]
+	if tx == nil || hex == p.Tx {
+		s.writeError(w, http.StatusBadRequest, "transaction is not signed")
+		return
+	}
+	wlt := tx.wallet
+
+	var total bitgo.Amount
+	for _, rcpt := range tx.recipients {
+		total += rcpt.Amount
+	}
+	if wlt.spendingLimit > 0 && total > wlt.spendingLimit {
+		a := s.pendingApproval(wlt, &bitgo.SendManyParams{
+			Recipients: tx.recipients,
+			FeeRate:    tx.feeRate,
+			SequenceID: p.SequenceID,
+			Message:    p.Message,
+			Instant:    p.Instant,
+		})
+		delete(s.unsigned, hex)
+		writeJSON(w, http.StatusAccepted, map[string]string{
+			"error":           "exceeds a spending limit",
+			"pendingApproval": a.ID,
+			"triggeredPolicy": spendingLimitPolicy,
+			"status":          bitgo.SendStatusPendingApproval,
+		})
+		return
+	}
+
+	unspent := make(map[string]bool, len(wlt.unspents))
+	for _, u := range wlt.unspents {
+		unspent[u.OutPoint()] = true
+	}
+	for _, u := range tx.inputs {
+		if !unspent[u.OutPoint()] {
+			s.writeError(w, http.StatusBadRequest, "transaction inputs were already spent")
+			return
+		}
+	}
+	delete(s.unsigned, hex)
+
+	t := s.sendTx(wlt, tx, p.SequenceID, p.Message, p.Instant)
+	writeJSON(w, http.StatusOK, bitgo.SendResult{
+		Status:      "accepted",
+		TxID:        t.ID,
+		Tx:          p.Tx,
+		Fee:         t.Fee,
+		FeeRate:     tx.feeRate,
+		Instant:     t.Instant,
+		TravelInfos: []bitgo.TravelInfo{},
+	})
+}
@@ -0,0 +1,320 @@
+// Package bitgotest provides a stateful in-memory fake of BitGo API v1 and BitGo Express
+// for testing code which uses bitgo.Client. Coin-scoped API v2 wallet endpoints are faked as well,
+// the wallets are shared by all coins.
+//
+//	srv := bitgotest.NewServer()
+//	defer srv.Close()
+//	srv.AddWallet("2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr", "root")
+//	srv.AddUnspents("2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr", bitgo.Unspent{Value: 1000, Confirmations: 6})
+//	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+package bitgotest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// Routes of the fake API which can be passed to Server.Fail.
+const (
+	RouteWallets       = "GET /api/v1/wallet"
+	RouteWallet        = "GET /api/v1/wallet/{id}"
+	RouteCreateAddress = "POST /api/v1/wallet/{id}/address/{chain}"
+	RouteAddresses     = "GET /api/v1/wallet/{id}/addresses"
+	RouteAddress       = "GET /api/v1/wallet/{id}/addresses/{address}"
+	RouteTransactions  = "GET /api/v1/wallet/{id}/tx"
+	RouteTransaction   = "GET /api/v1/wallet/{id}/tx/{hash}"
+	RouteSequenceID    = "GET /api/v1/wallet/{id}/tx/sequence/{sequenceId}"
+	RouteUnspents      = "GET /api/v1/wallet/{id}/unspents"
+	RouteCreateTx      = "POST /api/v1/wallet/{id}/createtransaction"
+	RouteSignTx        = "POST /api/v1/wallet/{id}/signtransaction"
+	RouteSendTx        = "POST /api/v1/tx/send"
+	RouteSendCoins     = "POST /api/v1/wallet/{id}/sendcoins"
+	RouteSendMany      = "POST /api/v1/wallet/{id}/sendmany"
+	RouteConsolidate   = "PUT /api/v1/wallet/{id}/consolidateunspents"
+	RouteFeeEstimate   = "GET /api/v1/tx/fee"
+	RouteApprovals     = "GET /api/v1/pendingapprovals"
+	RouteApproval      = "GET /api/v1/pendingapprovals/{id}"
+	RouteApprove       = "PUT /api/v1/pendingapprovals/{id}"
+	RouteLogin         = "POST /api/v1/user/login"
+	RouteLogout        = "GET /api/v1/user/logout"
+	RouteSession       = "GET /api/v1/user/session"
+	RouteUnlock        = "POST /api/v1/user/unlock"
+	RouteMe            = "GET /api/v1/user/me"
+	RouteAccessTokens  = "GET /api/v1/user/accesstoken"
+	RouteCreateToken   = "POST /api/v1/user/accesstoken"
+	RouteRevokeToken   = "DELETE /api/v1/user/accesstoken/{id}"
+
+	RouteCoinWallet      = "GET /api/v2/{coin}/wallet/{id}"
+	RouteCoinUnspents    = "GET /api/v2/{coin}/wallet/{id}/unspents"
+	RouteCoinConsolidate = "POST /api/v2/{coin}/wallet/{id}/consolidateUnspents"
+)
+
+// Failure describes an error injected into responses of a route, see Server.Fail.
+type Failure struct {
+	// StatusCode is HTTP status code of the response, e.g., 401, 429 or 503.
+	StatusCode int
+	// Body is the response body, by default it's a JSON object with the error message.
+	Body string
+	// RetryAfter sets Retry-After header in seconds if it's positive.
+	RetryAfter int
+	// Delay postpones the response to simulate timeouts.
+	// The server stops waiting when the client cancels the request.
+	Delay time.Duration
+	// Times is how many requests fail before the route recovers. Zero means all requests fail.
+	Times int
+}
+
+// Server is a fake BitGo API server. It's safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	wallets  map[string]*wallet
+	failures map[string]*Failure
+	// unsigned are transactions built by createtransaction endpoint by their hex.
+	unsigned map[string]*fakeTx
+	// feeRates are fee rates by block target, nil means defaultFeeRates are used.
+	feeRates map[int]bitgo.FeeRate
+	// users are users by their lowercase email.
+	users map[string]*user
+	// sessions are user sessions by their tokens.
+	sessions map[string]*session
+	// requireUnlock makes endpoints which spend coins require an unlocked session.
+	requireUnlock bool
+	// seq is used to generate IDs, addresses and transaction hashes.
+	seq int
+	// requestSeq is used to generate request IDs without holding the lock.
+	requestSeq atomic.Int64
+}
+
+// wallet is a state of a fake wallet.
+type wallet struct {
+	id           string
+	passphrase   string
+	addresses    []bitgo.Address
+	unspents     []bitgo.Unspent
+	transactions []bitgo.Transaction
+	approvals    []*approval
+	// spendingLimit is a max amount which can be sent without approval.
+	spendingLimit bitgo.Amount
+}
+
+// NewServer starts and returns a new fake server. The caller should call Close when finished.
+func NewServer() *Server {
+	s := Server{
+		wallets:  make(map[string]*wallet),
+		failures: make(map[string]*Failure),
+		unsigned: make(map[string]*fakeTx),
+		users:    make(map[string]*user),
+		sessions: make(map[string]*session),
+	}
+
+	mux := http.NewServeMux()
+	s.handle(mux, RouteWallets, s.listWallets)
+	s.handle(mux, RouteWallet, s.getWallet)
+	s.handle(mux, RouteCreateAddress, s.createAddress)
+	s.handle(mux, RouteAddresses, s.listAddresses)
+	s.handle(mux, RouteAddress, s.getAddress)
+	s.handle(mux, RouteTransactions, s.listTransactions)
+	s.handle(mux, RouteTransaction, s.getTransaction)
+	s.handle(mux, RouteSequenceID, s.getTransactionBySequenceID)
+	s.handle(mux, RouteUnspents, s.unspents)
+	s.handle(mux, RouteCreateTx, s.createTransaction)
+	s.handle(mux, RouteSignTx, s.signTransaction)
+	s.handle(mux, RouteSendTx, s.sendTransaction)
+	s.handle(mux, RouteSendCoins, s.sendCoins)
+	s.handle(mux, RouteSendMany, s.sendMany)
+	s.handle(mux, RouteConsolidate, s.consolidate)
+	s.handle(mux, RouteFeeEstimate, s.estimateFee)
+	s.handle(mux, RouteApprovals, s.listApprovals)
+	s.handle(mux, RouteApproval, s.getApproval)
+	s.handle(mux, RouteApprove, s.updateApproval)
+	s.handle(mux, RouteLogin, s.login)
+	s.handle(mux, RouteLogout, s.logout)
+	s.handle(mux, RouteSession, s.getSession)
+	s.handle(mux, RouteUnlock, s.unlock)
+	s.handle(mux, RouteMe, s.me)
+	s.handle(mux, RouteAccessTokens, s.listAccessTokens)
+	s.handle(mux, RouteCreateToken, s.createAccessToken)
+	s.handle(mux, RouteRevokeToken, s.revokeAccessToken)
+	s.handle(mux, RouteCoinWallet, s.getCoinWallet)
+	s.handle(mux, RouteCoinUnspents, s.coinUnspents)
+	s.handle(mux, RouteCoinConsolidate, s.coinConsolidate)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		s.writeError(w, http.StatusNotFound, "not found")
+	})
+
+	s.Server = httptest.NewServer(mux)
+	return &s
+}
+
+// AddWallet adds an empty wallet whose private key is decrypted with the passphrase.
+func (s *Server) AddWallet(id, passphrase string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wallets[id] = &wallet{id: id, passphrase: passphrase}
+}
+
+// AddUnspents adds unspents to the wallet creating the wallet if needed.
+// Unspents without a transaction hash get a unique one.
+func (s *Server) AddUnspents(walletID string, uu ...bitgo.Unspent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.wallets[walletID]
+	if w == nil {
+		w = &wallet{id: walletID}
+		s.wallets[walletID] = w
+	}
+	for _, u := range uu {
+		if u.TxHash == "" {
+			u.TxHash = s.txHash()
+		}
+		u.Wallet = walletID
+		w.unspents = append(w.unspents, u)
+	}
+}
+
+// Unspents returns current unspents of the wallet.
+func (s *Server) Unspents(walletID string) []bitgo.Unspent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.wallets[walletID]
+	if w == nil {
+		return nil
+	}
+	return append([]bitgo.Unspent(nil), w.unspents...)
+}
+
+// Fail injects the failure into responses of the route, e.g., RouteUnspents.
+// Empty route means all routes fail. A zero Failure removes the injected failure.
+func (s *Server) Fail(route string, f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f == (Failure{}) {
+		delete(s.failures, route)
+		return
+	}
+	s.failures[route] = &f
+}
+
+// handle registers the route handler which injects failures.
+// Requests signed by bitgo.HMACAuthenticator are verified and their responses are signed.
+func (s *Server) handle(mux *http.ServeMux, route string, h http.HandlerFunc) {
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		if f, ok := s.failure(route); ok {
+			s.writeFailure(w, r, f)
+			return
+		}
+		h(w, r)
+	}
+	mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("BitGo-Auth-Version") != "" {
+			s.serveHMAC(w, r, serve)
+			return
+		}
+		serve(w, r)
+	})
+}
+
+// failure returns a failure injected into the route and counts it.
+func (s *Server) failure(route string) (Failure, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range []string{route, ""} {
+		f := s.failures[key]
+		if f == nil {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				delete(s.failures, key)
+			}
+		}
+		return *f, true
+	}
+	return Failure{}, false
+}
+
+func (s *Server) writeFailure(w http.ResponseWriter, r *http.Request, f Failure) {
+	if f.Delay > 0 {
+		t := time.NewTimer(f.Delay)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+	}
+	if f.StatusCode == 0 {
+		f.StatusCode = http.StatusServiceUnavailable
+	}
+	if f.Body != "" {
+		w.WriteHeader(f.StatusCode)
+		fmt.Fprint(w, f.Body)
+		return
+	}
+	s.writeError(w, f.StatusCode, http.StatusText(f.StatusCode))
+}
+
+// writeError writes an error in BitGo format, e.g., {"error":"wallet not found","requestId":"..."}.
+// It's safe to call while holding the lock.
+func (s *Server) writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{
+		"error":     msg,
+		"requestId": fmt.Sprintf("fake%021d", s.requestSeq.Add(1)),
+	})
+}
+
+// writeNeeds writes 401 error which asks to unlock the session or provide OTP,
+// e.g., {"error":"needs unlock","needsUnlock":true,"requestId":"..."}.
+// It's safe to call while holding the lock.
+func (s *Server) writeNeeds(w http.ResponseWriter, field, msg string) {
+	writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+		"error":     msg,
+		field:       true,
+		"requestId": fmt.Sprintf("fake%021d", s.requestSeq.Add(1)),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// objectID returns a unique ID in the format of BitGo object IDs. The caller must hold the lock.
+func (s *Server) objectID() string {
+	s.seq++
+	return fmt.Sprintf("%024x", s.seq)
+}
+
+// address returns a unique P2SH testnet address. The caller must hold the lock.
+func (s *Server) address() string {
+	s.seq++
+	h := sha256.Sum256([]byte("address" + strconv.Itoa(s.seq)))
+	return "2N" + hex.EncodeToString(h[:])[:33]
+}
+
+// txHash returns a unique transaction hash. The caller must hold the lock.
+func (s *Server) txHash() string {
+	s.seq++
+	h := sha256.Sum256([]byte(strconv.Itoa(s.seq)))
+	return hex.EncodeToString(h[:])
+}
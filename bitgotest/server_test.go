@@ -0,0 +1,863 @@
+package bitgotest_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+	"github.com/marselester/bitgo-v1/bitgotest"
+)
+
+const walletID = "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr"
+
+func newServer() *bitgotest.Server {
+	srv := bitgotest.NewServer()
+	srv.AddWallet(walletID, "root")
+	for i := 1; i <= 5; i++ {
+		srv.AddUnspents(walletID, bitgo.Unspent{
+			Value:         bitgo.Amount(i * 10000),
+			Confirmations: i,
+			ChainPath:     "/1/0",
+		})
+	}
+	return srv
+}
+
+func TestUnspents(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+
+	tests := []struct {
+		name   string
+		params bitgo.UnspentsParams
+		want   []bitgo.Amount
+	}{
+		{"paginated", bitgo.UnspentsParams{Limit: 2}, []bitgo.Amount{10000, 20000, 30000, 40000, 50000}},
+		{"skip", bitgo.UnspentsParams{Limit: 2, Skip: 3}, []bitgo.Amount{40000, 50000}},
+		{"min confirms", bitgo.UnspentsParams{MinConfirms: 4}, []bitgo.Amount{40000, 50000}},
+		{"min size", bitgo.UnspentsParams{MinSize: 30000}, []bitgo.Amount{30000, 40000, 50000}},
+		{"target", bitgo.UnspentsParams{Target: 25000}, []bitgo.Amount{10000, 20000}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []bitgo.Amount
+			var pages int
+			err := c.Wallet.Unspents(context.Background(), walletID, &test.params, func(list *bitgo.UnspentList) {
+				pages++
+				for _, u := range list.Unspents {
+					got = append(got, u.Value)
+				}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("should be %v, not %v", test.want, got)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("should be %v, not %v", test.want, got)
+				}
+			}
+			if test.params.Limit == 2 && pages != (len(test.want)+1)/2 {
+				t.Errorf("unexpected number of pages %d", pages)
+			}
+		})
+	}
+}
+
+func TestUnspentsNotFound(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+
+	err := c.Wallet.Unspents(context.Background(), "unknown", nil, func(*bitgo.UnspentList) {})
+	if e, ok := err.(bitgo.Error); !ok || !e.IsNotFound() || e.RequestID == "" {
+		t.Fatalf("expected not found error, got %#v", err)
+	}
+}
+
+func TestUnspentsSegwit(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	for _, path := range []string{"/10/0", "/11/0", "/20/0", "/21/0"} {
+		srv.AddUnspents(walletID, bitgo.Unspent{Value: 1000, ChainPath: path})
+	}
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+
+	segwit := false
+	var got []string
+	err := c.Wallet.Unspents(context.Background(), walletID, &bitgo.UnspentsParams{Segwit: &segwit}, func(list *bitgo.UnspentList) {
+		for _, u := range list.Unspents {
+			got = append(got, u.ChainPath)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 5 {
+		t.Errorf("expected only 5 legacy unspents, got %v", got)
+	}
+}
+
+func TestConsolidate(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+
+	_, err := c.Wallet.Consolidate(context.Background(), walletID, &bitgo.WalletConsolidateParams{
+		WalletPassphrase: "wrong",
+	})
+	if e, ok := err.(bitgo.Error); !ok || !e.IsInvalidRequest() {
+		t.Fatalf("expected invalid passphrase error, got %#v", err)
+	}
+
+	tt, err := c.Wallet.Consolidate(context.Background(), walletID, &bitgo.WalletConsolidateParams{
+		WalletPassphrase: "root",
+		MinValue:         20000,
+		MaxValue:         40000,
+		FeeRate:          1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tt) != 1 || tt[0].Fee != 930 {
+		t.Fatalf("unexpected transactions %#v", tt)
+	}
+
+	uu := srv.Unspents(walletID)
+	if len(uu) != 3 {
+		t.Fatalf("3 inputs must be replaced with 1 output, got %d unspents", len(uu))
+	}
+	want := []bitgo.Amount{10000, 50000, 90000 - 930}
+	for i, u := range uu {
+		if u.Value != want[i] {
+			t.Errorf("unspent %d should be %d, not %d", i, want[i], u.Value)
+		}
+	}
+	if uu[2].TxHash != tt[0].TxID || !uu[2].IsChange {
+		t.Errorf("unexpected consolidated unspent %#v", uu[2])
+	}
+}
+
+func TestFail(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	srv.Fail(bitgotest.RouteUnspents, bitgotest.Failure{
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: 1,
+		Times:      2,
+	})
+	// The client doesn't retry when the server asks to wait longer than MaxBackoff.
+	c := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: 3,
+			MaxBackoff:  time.Millisecond,
+		}),
+	)
+	for i := 0; i < 2; i++ {
+		err := c.Wallet.Unspents(context.Background(), walletID, nil, func(*bitgo.UnspentList) {})
+		if e, ok := err.(bitgo.Error); !ok || !e.IsRateLimited() || e.RetryAfter != time.Second {
+			t.Fatalf("expected rate limit error with retry after 1s, got %#v", err)
+		}
+	}
+	if err := c.Wallet.Unspents(context.Background(), walletID, nil, func(*bitgo.UnspentList) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Fail("", bitgotest.Failure{StatusCode: http.StatusUnauthorized})
+	_, err := c.Wallet.Consolidate(context.Background(), walletID, nil)
+	if e, ok := err.(bitgo.Error); !ok || !e.IsUnauthorized() {
+		t.Fatalf("expected unauthorized error, got %#v", err)
+	}
+	srv.Fail("", bitgotest.Failure{})
+
+	srv.Fail(bitgotest.RouteUnspents, bitgotest.Failure{Delay: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = c.Wallet.Unspents(ctx, walletID, nil, func(*bitgo.UnspentList) {})
+	if ctx.Err() == nil || err == nil {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
+
+func TestWallets(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	srv.AddWallet("2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4", "")
+	srv.AddUnspents(walletID, bitgo.Unspent{Value: 1000, IsChange: true})
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+
+	var ids []string
+	for wlt, err := range c.Wallet.List(&bitgo.WalletListParams{Limit: 1}).All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, wlt.ID)
+	}
+	if len(ids) != 2 || ids[0] != "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4" || ids[1] != walletID {
+		t.Errorf("unexpected wallets %q", ids)
+	}
+
+	wlt, err := c.Wallet.Get(context.Background(), walletID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wlt.Balance != 151000 || wlt.ConfirmedBalance != 150000 || wlt.SpendableBalance != 151000 {
+		t.Errorf("unexpected balances %s, %s, %s", wlt.Balance, wlt.ConfirmedBalance, wlt.SpendableBalance)
+	}
+
+	_, err = c.Wallet.Get(context.Background(), "unknown")
+	if e, ok := err.(bitgo.Error); !ok || !e.IsNotFound() {
+		t.Fatalf("expected not found error, got %#v", err)
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	var created []*bitgo.Address
+	for _, chain := range []bitgo.Chain{bitgo.ChainP2SHP2WSH, bitgo.ChainP2SHP2WSH, bitgo.ChainP2WSH} {
+		a, err := c.Wallet.CreateAddress(ctx, walletID, chain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, a)
+	}
+	if a := created[1]; a.Index != 1 || a.Path != "/10/1" {
+		t.Errorf("unexpected address %#v", a)
+	}
+	srv.AddUnspents(walletID, bitgo.Unspent{Address: created[0].Address, Value: 1000})
+
+	chain := bitgo.ChainP2SHP2WSH
+	var n int
+	for a, err := range c.Wallet.Addresses(walletID, &bitgo.AddressesParams{Chain: &chain, Limit: 1}).All(ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Chain != chain {
+			t.Errorf("unexpected chain %d", a.Chain)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("expected 2 addresses, got %d", n)
+	}
+
+	a, err := c.Wallet.Address(ctx, walletID, created[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Balance != 1000 || a.TxCount != 1 {
+		t.Errorf("unexpected address %#v", a)
+	}
+
+	_, err = c.Wallet.CreateAddress(ctx, walletID, 2)
+	if e, ok := err.(bitgo.Error); !ok || !e.IsInvalidRequest() {
+		t.Fatalf("expected invalid request error, got %#v", err)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	srv.AddTransactions(walletID,
+		bitgo.Transaction{Height: 100, Date: time.Date(2015, 9, 1, 0, 0, 0, 0, time.UTC), SequenceID: "first"},
+		bitgo.Transaction{Height: 200, Date: time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)},
+	)
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	tt, err := c.Wallet.Consolidate(ctx, walletID, &bitgo.WalletConsolidateParams{WalletPassphrase: "root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var heights []int
+	for tx, err := range c.Wallet.Transactions(walletID, &bitgo.TransactionsParams{Limit: 1}).All(ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		heights = append(heights, tx.Height)
+	}
+	if len(heights) != 3 || heights[0] != 0 || heights[1] != 200 || heights[2] != 100 {
+		t.Errorf("expected the latest transactions first, got heights %v", heights)
+	}
+
+	params := bitgo.TransactionsParams{
+		MinHeight: 100,
+		MaxDate:   time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	p := c.Wallet.Transactions(walletID, &params)
+	if !p.Next(ctx) {
+		t.Fatal(p.Err())
+	}
+	if items := p.Page().Items; len(items) != 1 || items[0].SequenceID != "first" {
+		t.Errorf("unexpected transactions %#v", items)
+	}
+
+	tx, err := c.Wallet.Transaction(ctx, walletID, tt[0].TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Inputs) != 5 || len(tx.Outputs) != 1 || tx.Fee != tt[0].Fee || !tx.Pending {
+		t.Errorf("unexpected consolidation transaction %#v", tx)
+	}
+
+	tx, err = c.Wallet.TransactionBySequenceID(ctx, walletID, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Height != 100 {
+		t.Errorf("unexpected transaction %#v", tx)
+	}
+}
+
+func TestSend(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	res, err := c.Wallet.SendCoins(ctx, walletID, &bitgo.SendCoinsParams{
+		Address:          "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+		Amount:           25000,
+		WalletPassphrase: "root",
+		FeeRate:          1000,
+		MinConfirms:      2,
+		SequenceID:       "withdrawal-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unspents of 20000 and 30000 with 2 and 3 confirmations are spent.
+	if res.RequiresApproval() || res.Fee != 670 {
+		t.Fatalf("unexpected result %#v", res)
+	}
+	wlt, err := c.Wallet.Get(ctx, walletID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := bitgo.Amount(150000 - 25000 - 670); wlt.Balance != want {
+		t.Errorf("expected balance %s, got %s", want, wlt.Balance)
+	}
+	tx, err := c.Wallet.TransactionBySequenceID(ctx, walletID, "withdrawal-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ID != res.TxID || len(tx.Inputs) != 2 || len(tx.Outputs) != 2 {
+		t.Errorf("unexpected transaction %#v", tx)
+	}
+
+	_, err = c.Wallet.SendCoins(ctx, walletID, &bitgo.SendCoinsParams{
+		Address:          "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+		Amount:           1000000,
+		WalletPassphrase: "root",
+	})
+	if e, ok := err.(bitgo.Error); !ok || !e.IsInvalidRequest() {
+		t.Fatalf("expected insufficient funds error, got %#v", err)
+	}
+
+	srv.SetSpendingLimit(walletID, 50000)
+	res, err = c.Wallet.SendMany(ctx, walletID, &bitgo.SendManyParams{
+		Recipients: []bitgo.Recipient{
+			{Address: "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm", Amount: 30000},
+			{Address: "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4", Amount: 30000},
+		},
+		WalletPassphrase: "root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.RequiresApproval() || res.PendingApproval == "" {
+		t.Fatalf("expected the transaction to require approval, got %#v", res)
+	}
+	if wlt, err = c.Wallet.Get(ctx, walletID); err != nil {
+		t.Fatal(err)
+	}
+	if len(wlt.PendingApprovals) != 1 || wlt.PendingApprovals[0].ID != res.PendingApproval {
+		t.Errorf("unexpected pending approvals %#v", wlt.PendingApprovals)
+	}
+}
+
+func TestCreateSignSendTransaction(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	unsigned, err := c.Wallet.CreateTransaction(ctx, walletID, &bitgo.CreateTransactionParams{
+		Recipients: []bitgo.Recipient{{Address: "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm", Amount: 15000}},
+		FeeRate:    1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unsigned.Unspents) != 2 || unsigned.Fee != 670 || len(unsigned.ChangeAddresses) != 1 {
+		t.Fatalf("unexpected unsigned transaction: %d inputs, fee %s, change %v", len(unsigned.Unspents), unsigned.Fee, unsigned.ChangeAddresses)
+	}
+	if want := unsigned.InputAmount() - 15000 - unsigned.Fee; unsigned.ChangeAddresses[0].Amount != want {
+		t.Errorf("expected change %s, got %s", want, unsigned.ChangeAddresses[0].Amount)
+	}
+	if uu := srv.Unspents(walletID); len(uu) != 5 {
+		t.Errorf("building a transaction must not spend unspents, got %d", len(uu))
+	}
+
+	signed, err := c.Wallet.SignTransaction(ctx, walletID, &bitgo.SignTransactionParams{
+		TransactionHex: unsigned.TransactionHex,
+		Unspents:       unsigned.Unspents,
+		Keychain:       &bitgo.Keychain{XPrv: "xprv"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Wallet.SendTransaction(ctx, &bitgo.SendTransactionParams{Tx: unsigned.TransactionHex})
+	if e, ok := err.(bitgo.Error); !ok || !e.IsInvalidRequest() {
+		t.Fatalf("expected unsigned transaction error, got %#v", err)
+	}
+	res, err := c.Wallet.SendTransaction(ctx, &bitgo.SendTransactionParams{Tx: signed.Tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fee != unsigned.Fee {
+		t.Errorf("unexpected result %#v", res)
+	}
+	if uu := srv.Unspents(walletID); len(uu) != 4 {
+		t.Errorf("expected 2 inputs spent and change added, got %d unspents", len(uu))
+	}
+}
+
+func TestFeeEstimate(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	fee, err := c.Fee.Estimate(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.NumBlocks != 2 || fee.FeePerKB != 15000 || len(fee.FeeByBlockTarget) != 4 {
+		t.Errorf("unexpected default estimate %d blocks %d sat/kB", fee.NumBlocks, fee.FeePerKB)
+	}
+
+	srv.SetFeeRates(map[int]bitgo.FeeRate{3: 8000, 10: 2000})
+	for numBlocks, want := range map[int]bitgo.FeeRate{1: 8000, 3: 8000, 9: 8000, 10: 2000, 100: 2000} {
+		fee, err = c.Fee.Estimate(context.Background(), numBlocks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fee.FeePerKB != want || fee.ForBlocks(numBlocks) != want {
+			t.Errorf("expected %d sat/kB within %d blocks, got %d", want, numBlocks, fee.FeePerKB)
+		}
+	}
+
+	srv.SetFeeRates(map[int]bitgo.FeeRate{})
+	_, err = c.Fee.Estimate(context.Background(), 0)
+	if e, ok := err.(bitgo.Error); !ok || e.HTTPStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected unavailable estimates, got %v", err)
+	}
+}
+
+func TestPendingApprovals(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithApprovalPollInterval(time.Millisecond),
+	)
+	ctx := context.Background()
+
+	srv.SetSpendingLimit(walletID, 50000)
+	var ids []string
+	for _, seqID := range []string{"withdrawal-1", "withdrawal-2"} {
+		res, err := c.Wallet.SendCoins(ctx, walletID, &bitgo.SendCoinsParams{
+			Address:          "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+			Amount:           60000,
+			WalletPassphrase: "root",
+			SequenceID:       seqID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.RequiresApproval() {
+			t.Fatalf("expected the transaction to require approval, got %#v", res)
+		}
+		ids = append(ids, res.PendingApproval)
+	}
+
+	list, err := c.PendingApprovals.List(ctx, &bitgo.PendingApprovalsParams{WalletID: walletID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != ids[0] || list[1].ID != ids[1] {
+		t.Fatalf("unexpected pending approvals %#v", list)
+	}
+	if r := list[0].Info.TransactionRequest; r == nil || r.RequestedAmount != 60000 || r.SequenceID != "withdrawal-1" {
+		t.Errorf("unexpected transaction request %#v", r)
+	}
+
+	_, err = c.PendingApprovals.Approve(ctx, ids[0], nil)
+	if e, ok := err.(bitgo.Error); !ok || !e.IsOTPRequired() {
+		t.Fatalf("expected OTP error, got %#v", err)
+	}
+
+	done := make(chan *bitgo.PendingApproval)
+	go func() {
+		a, err := c.PendingApprovals.WaitForApproval(ctx, ids[0])
+		if err != nil {
+			t.Error(err)
+		}
+		done <- a
+	}()
+	if _, err = c.PendingApprovals.Approve(ctx, ids[0], &bitgo.ApproveParams{OTP: "0000000"}); err != nil {
+		t.Fatal(err)
+	}
+	if a := <-done; a == nil || a.State != bitgo.ApprovalStateApproved {
+		t.Fatalf("expected approved approval, got %#v", a)
+	}
+	tx, err := c.Wallet.TransactionBySequenceID(ctx, walletID, "withdrawal-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.PendingApproval != ids[0] {
+		t.Errorf("expected the transaction to be sent by approval %s, got %#v", ids[0], tx)
+	}
+
+	a, err := c.PendingApprovals.Reject(ctx, ids[1], "0000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.State != bitgo.ApprovalStateRejected {
+		t.Errorf("expected rejected approval, got %q", a.State)
+	}
+	if _, err = c.Wallet.TransactionBySequenceID(ctx, walletID, "withdrawal-2"); err == nil {
+		t.Errorf("rejected transaction must not be sent")
+	}
+	if _, err = c.PendingApprovals.Approve(ctx, ids[1], &bitgo.ApproveParams{OTP: "0000000"}); err == nil {
+		t.Errorf("resolved approval must not be approved")
+	}
+
+	list, err = c.PendingApprovals.List(ctx, &bitgo.PendingApprovalsParams{WalletID: walletID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no pending approvals, got %d", len(list))
+	}
+}
+
+func TestUser(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	srv.AddUser("alice@example.com", "correct horse")
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	for _, p := range []*bitgo.LoginParams{
+		{Email: "alice@example.com", Password: "wrong", OTP: bitgotest.OTP},
+		{Email: "alice@example.com", Password: "correct horse", OTP: "1234567"},
+	} {
+		_, err := c.User.Login(ctx, p)
+		if e, ok := err.(bitgo.Error); !ok || !e.IsUnauthorized() {
+			t.Fatalf("expected unauthorized error, got %#v", err)
+		}
+	}
+	if _, err := c.User.Me(ctx); err == nil {
+		t.Fatal("expected unauthorized error")
+	}
+
+	res, err := c.User.Login(ctx, &bitgo.LoginParams{
+		Email:    "Alice@example.com",
+		Password: "correct horse",
+		OTP:      bitgotest.OTP,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	me, err := c.User.Me(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.ID != res.User.ID || me.Username != "alice@example.com" {
+		t.Errorf("unexpected user %#v", me)
+	}
+
+	sess, err := c.User.Session(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.User != me.ID || sess.IsUnlocked(time.Now()) {
+		t.Errorf("unexpected session %#v", sess)
+	}
+	if _, err = c.User.Unlock(ctx, "1234567", time.Minute); err == nil {
+		t.Fatal("expected incorrect OTP error")
+	}
+	if sess, err = c.User.Unlock(ctx, bitgotest.OTP, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !sess.IsUnlocked(time.Now()) || sess.IsUnlocked(time.Now().Add(2*time.Minute)) {
+		t.Errorf("expected the session to be unlocked for a minute, got %#v", sess.Unlock)
+	}
+
+	token := c.SessionToken()
+	if err = c.User.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	c.SetSessionToken(token)
+	if _, err = c.User.Session(ctx); err == nil {
+		t.Errorf("expected the session to be ended")
+	}
+}
+
+func TestRequireUnlock(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	srv.AddUser("alice@example.com", "correct horse")
+	srv.RequireUnlock(true)
+	ctx := context.Background()
+
+	var otps int
+	c := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithOTPProvider(func(ctx context.Context) (string, error) {
+			otps++
+			return bitgotest.OTP, nil
+		}),
+	)
+	params := &bitgo.SendCoinsParams{
+		Address:          "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+		Amount:           25000,
+		WalletPassphrase: "root",
+	}
+	_, err := c.Wallet.SendCoins(ctx, walletID, params)
+	if e, ok := err.(bitgo.Error); !ok || !e.IsUnauthorized() {
+		t.Fatalf("expected unauthorized error without a session, got %#v", err)
+	}
+
+	if _, err = c.User.Login(ctx, &bitgo.LoginParams{Email: "alice@example.com", Password: "correct horse"}); err == nil {
+		t.Fatal("expected needs OTP error")
+	} else if e, ok := err.(bitgo.Error); !ok || !e.IsOTPRequired() {
+		t.Fatalf("expected needs OTP error, got %#v", err)
+	}
+	_, err = c.User.Login(ctx, &bitgo.LoginParams{Email: "alice@example.com", Password: "correct horse", OTP: bitgotest.OTP})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.Wallet.SendCoins(ctx, walletID, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TxID == "" || otps != 1 {
+		t.Errorf("expected the coins to be sent after a single unlock, got %#v after %d OTPs", res, otps)
+	}
+	if _, err = c.Wallet.SendCoins(ctx, walletID, params); err != nil {
+		t.Fatal(err)
+	}
+	if otps != 1 {
+		t.Errorf("unlocked session must not be unlocked again, got %d OTPs", otps)
+	}
+
+	c = bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	if _, err = c.User.Login(ctx, &bitgo.LoginParams{Email: "alice@example.com", Password: "correct horse", OTP: bitgotest.OTP}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Wallet.Consolidate(ctx, walletID, &bitgo.WalletConsolidateParams{WalletPassphrase: "root"})
+	if e, ok := err.(bitgo.Error); !ok || !e.IsUnlockRequired() {
+		t.Fatalf("expected needs unlock error, got %#v", err)
+	}
+}
+
+func TestAccessTokens(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	srv.AddUser("alice@example.com", "correct horse")
+	srv.RequireUnlock(true)
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	if _, err := c.User.Login(ctx, &bitgo.LoginParams{Email: "alice@example.com", Password: "correct horse", OTP: bitgotest.OTP}); err != nil {
+		t.Fatal(err)
+	}
+	params := &bitgo.AccessTokenParams{
+		Label:        "payouts",
+		Scope:        []bitgo.Scope{bitgo.ScopeWalletViewAll, bitgo.ScopeWalletSpendAll},
+		IPRestrict:   []string{"127.0.0.0/8"},
+		TxValueLimit: 100000000,
+	}
+	_, err := c.User.CreateAccessToken(ctx, params)
+	if e, ok := err.(bitgo.Error); !ok || !e.IsOTPRequired() {
+		t.Fatalf("expected needs OTP error, got %#v", err)
+	}
+	params.OTP = bitgotest.OTP
+	payouts, err := c.User.CreateAccessToken(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocked, err := c.User.CreateAccessToken(ctx, &bitgo.AccessTokenParams{
+		Label:      "blocked",
+		OTP:        bitgotest.OTP,
+		Scope:      []bitgo.Scope{bitgo.ScopeWalletViewAll},
+		IPRestrict: []string{"203.0.113.7"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := c.User.AccessTokens(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 2 {
+		t.Fatalf("expected 2 access tokens, got %d", len(tokens))
+	}
+	for _, tok := range tokens {
+		if tok.Token != "" {
+			t.Errorf("listed access token %q must not reveal the token", tok.Label)
+		}
+	}
+
+	// The token with a spending limit can send coins without an unlock.
+	tc := bitgo.NewClient(bitgo.WithBaseURL(srv.URL), bitgo.WithAccesToken(payouts.Token))
+	sendParams := &bitgo.SendCoinsParams{
+		Address:          "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+		Amount:           25000,
+		WalletPassphrase: "root",
+	}
+	if _, err = tc.Wallet.SendCoins(ctx, walletID, sendParams); err != nil {
+		t.Fatal(err)
+	}
+	sess, err := tc.User.Session(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sess.Scope, params.Scope) {
+		t.Errorf("expected %v scope, got %v", params.Scope, sess.Scope)
+	}
+
+	// The token restricted to another IP is rejected.
+	bc := bitgo.NewClient(bitgo.WithBaseURL(srv.URL), bitgo.WithAccesToken(blocked.Token))
+	if _, err = bc.User.Me(ctx); err == nil {
+		t.Error("expected IP restricted access token to be rejected")
+	}
+
+	if err = c.User.RevokeAccessToken(ctx, payouts.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.User.RevokeAccessToken(ctx, payouts.ID); err == nil {
+		t.Error("expected revoked access token to be not found")
+	}
+	if _, err = tc.User.Me(ctx); err == nil {
+		t.Error("expected revoked access token to be rejected")
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	srv.AddUser("alice@example.com", "correct horse")
+	srv.RequireUnlock(true)
+	c := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAuthenticator(bitgo.HMACAuthenticator{}),
+	)
+	ctx := context.Background()
+
+	if _, err := c.User.Login(ctx, &bitgo.LoginParams{Email: "alice@example.com", Password: "correct horse", OTP: bitgotest.OTP}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.User.Unlock(ctx, bitgotest.OTP, 0); err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Wallet.SendCoins(ctx, walletID, &bitgo.SendCoinsParams{
+		Address:          "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+		Amount:           25000,
+		WalletPassphrase: "root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TxID == "" {
+		t.Errorf("expected the coins to be sent, got %#v", res)
+	}
+
+	// Tokens unknown to the server are rejected while the session token works in another client.
+	token := c.SessionToken()
+	other := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("swordfish"),
+		bitgo.WithAuthenticator(bitgo.HMACAuthenticator{}),
+	)
+	if _, err = other.User.Me(ctx); err == nil {
+		t.Error("expected unknown token to be rejected")
+	}
+	other.SetSessionToken(token)
+	if _, err = other.User.Me(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCoinWallet(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	ctx := context.Background()
+	tbtc := c.Coin("tbtc").Wallet
+
+	wlt, err := tbtc.Get(ctx, walletID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wlt.ID != walletID || wlt.Balance != 150000 || wlt.AdminCount != 1 || !wlt.IsActive {
+		t.Errorf("unexpected wallet %#v", wlt)
+	}
+
+	var got []bitgo.Amount
+	var pages int
+	p := tbtc.UnspentsPager(walletID, &bitgo.UnspentsParams{Limit: 2, MinConfirms: 2})
+	for p.Next(ctx) {
+		pages++
+		for _, u := range p.Page().Items {
+			if u.ChainPath != "/1/0" || !u.IsChange {
+				t.Errorf("unexpected unspent %#v", u)
+			}
+			got = append(got, u.Value)
+		}
+	}
+	if err = p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []bitgo.Amount{20000, 30000, 40000, 50000}
+	if !reflect.DeepEqual(got, want) || pages != 2 {
+		t.Errorf("expected %v in 2 pages, got %v in %d pages", want, got, pages)
+	}
+
+	tx, err := tbtc.Consolidate(ctx, walletID, &bitgo.WalletConsolidateParams{
+		WalletPassphrase: "root",
+		MinValue:         20000,
+		MaxValue:         40000,
+		FeeRate:          1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxID == "" || tx.Fee != 930 {
+		t.Errorf("unexpected transaction %#v", tx)
+	}
+	if uu := srv.Unspents(walletID); len(uu) != 3 {
+		t.Errorf("3 inputs must be replaced with 1 output, got %d unspents", len(uu))
+	}
+
+	if _, err = c.Coin("tbtc").Wallet.Get(ctx, "unknown"); err == nil {
+		t.Error("expected wallet not found error")
+	}
+}
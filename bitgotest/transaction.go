@@ -0,0 +1,152 @@
+package bitgotest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// AddTransactions adds transactions to the wallet history creating the wallet if needed.
+// Transactions without a hash get a unique one.
+func (s *Server) AddTransactions(walletID string, tt ...bitgo.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.wallets[walletID]
+	if w == nil {
+		w = &wallet{id: walletID}
+		s.wallets[walletID] = w
+	}
+	for _, t := range tt {
+		if t.ID == "" {
+			t.ID = s.txHash()
+		}
+		w.transactions = append(w.transactions, t)
+	}
+}
+
+// listTransactions lists wallet transactions, the latest first,
+// filtered by minHeight and dates and paginated with skip and limit.
+func (s *Server) listTransactions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var p struct {
+		skip, limit, minHeight int
+		minDate, maxDate       time.Time
+	}
+	var err error
+	for name, v := range map[string]*int{
+		"skip":      &p.skip,
+		"limit":     &p.limit,
+		"minHeight": &p.minHeight,
+	} {
+		if *v, err = intParam(q.Get(name)); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid "+name)
+			return
+		}
+	}
+	for name, v := range map[string]*time.Time{
+		"minDate": &p.minDate,
+		"maxDate": &p.maxDate,
+	} {
+		if q.Get(name) == "" {
+			continue
+		}
+		if *v, err = time.Parse(time.RFC3339, q.Get(name)); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid "+name)
+			return
+		}
+	}
+	if p.limit == 0 {
+		p.limit = defaultTransactionsLimit
+	}
+	if p.limit > maxTransactionsLimit {
+		s.writeError(w, http.StatusBadRequest, "limit must be at most 500")
+		return
+	}
+	compact := q.Get("compact") == "true"
+
+	s.mu.Lock()
+	wlt := s.wallets[r.PathValue("id")]
+	var matched []bitgo.Transaction
+	if wlt != nil {
+		for i := len(wlt.transactions) - 1; i >= 0; i-- {
+			t := wlt.transactions[i]
+			switch {
+			case t.Height < p.minHeight:
+				continue
+			case !p.minDate.IsZero() && t.Date.Before(p.minDate):
+				continue
+			case !p.maxDate.IsZero() && !t.Date.Before(p.maxDate):
+				continue
+			}
+			if compact {
+				t.Inputs, t.Outputs = nil, nil
+			}
+			matched = append(matched, t)
+		}
+	}
+	s.mu.Unlock()
+
+	if wlt == nil {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	list := bitgo.TransactionList{
+		ListMeta: bitgo.ListMeta{
+			Start: p.skip,
+			Total: len(matched),
+		},
+		Transactions: []bitgo.Transaction{},
+	}
+	if p.skip < len(matched) {
+		end := p.skip + p.limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		list.Transactions = matched[p.skip:end]
+	}
+	list.Count = len(list.Transactions)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// getTransaction returns the wallet transaction by its hash.
+func (s *Server) getTransaction(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.findTransaction(r.PathValue("id"), func(t *bitgo.Transaction) bool {
+		return t.ID == r.PathValue("hash")
+	})
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+// getTransactionBySequenceID returns the wallet transaction by its sequence ID.
+func (s *Server) getTransactionBySequenceID(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.findTransaction(r.PathValue("id"), func(t *bitgo.Transaction) bool {
+		return t.SequenceID == r.PathValue("sequenceId")
+	})
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bitgo.Transaction{"transaction": t})
+}
+
+// findTransaction returns the first wallet transaction which matches.
+func (s *Server) findTransaction(walletID string, match func(*bitgo.Transaction) bool) (bitgo.Transaction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wlt := s.wallets[walletID]
+	if wlt == nil {
+		return bitgo.Transaction{}, false
+	}
+	for i := range wlt.transactions {
+		if match(&wlt.transactions[i]) {
+			return wlt.transactions[i], true
+		}
+	}
+	return bitgo.Transaction{}, false
+}
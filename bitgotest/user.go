@@ -0,0 +1,289 @@
+package bitgotest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// OTP is the only one-time password accepted by the fake server as in BitGo test environment.
+const OTP = "0000000"
+
+// Durations of fake sessions.
+const (
+	sessionDuration       = time.Hour
+	defaultUnlockDuration = 10 * time.Minute
+)
+
+// user is a fake user who can log in.
+type user struct {
+	bitgo.User
+	// passwordHMAC is HMAC-SHA256 of the password keyed with the email.
+	passwordHMAC string
+}
+
+// session is a fake user session identified by its token.
+type session struct {
+	user    *user
+	created time.Time
+	expires time.Time
+	unlock  *bitgo.SessionUnlock
+	// accessToken is set if the session is a long-lived access token.
+	accessToken *bitgo.AccessToken
+}
+
+// info returns the session as returned by the API.
+func (s *session) info() bitgo.Session {
+	info := bitgo.Session{
+		Client:  "bitgo",
+		User:    s.user.ID,
+		Scope:   []bitgo.Scope{bitgo.ScopeOpenID, bitgo.ScopeProfile, bitgo.ScopeWalletSpendAll, bitgo.ScopeWalletViewAll},
+		Created: s.created,
+		Expires: s.expires,
+		Origin:  "test.bitgo.com",
+		Unlock:  s.unlock,
+	}
+	if s.accessToken != nil {
+		info.Scope = s.accessToken.Scope
+	}
+	return info
+}
+
+// AddUser adds a user who can log in with the email, the password and OTP.
+func (s *Server) AddUser(email, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email = strings.ToLower(email)
+	mac := hmac.New(sha256.New, []byte(email))
+	mac.Write([]byte(password))
+	s.users[email] = &user{
+		User: bitgo.User{
+			ID:       s.objectID(),
+			Username: email,
+			Email:    bitgo.UserEmail{Email: email, Verified: true},
+			IsActive: true,
+			OTPDevices: []bitgo.OTPDevice{
+				{ID: s.objectID(), Type: "totp", Label: "fake"},
+			},
+		},
+		passwordHMAC: hex.EncodeToString(mac.Sum(nil)),
+	}
+}
+
+// RequireUnlock makes endpoints which spend coins (send coins, send transaction and consolidate)
+// require an unlocked session of a logged in user, otherwise they fail with "needs unlock" error.
+func (s *Server) RequireUnlock(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requireUnlock = enabled
+}
+
+// checkUnlock writes an error and returns false if the server requires an unlock
+// and the session of the request isn't unlocked.
+func (s *Server) checkUnlock(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.requireUnlock {
+		return true
+	}
+	sess := s.authenticate(r)
+	if sess == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return false
+	}
+	// Access tokens with a spending limit don't need an unlock.
+	if sess.accessToken != nil && sess.accessToken.TxValueLimit > 0 {
+		return true
+	}
+	if info := sess.info(); !info.IsUnlocked(time.Now()) {
+		s.writeNeeds(w, "needsUnlock", "needs unlock")
+		return false
+	}
+	return true
+}
+
+// authenticate returns the session of the request's bearer token.
+// Access tokens are only accepted from their allowed IP addresses. The caller must hold the lock.
+func (s *Server) authenticate(r *http.Request) *session {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil
+	}
+	sess := s.sessions[token]
+	if sess == nil || time.Now().After(sess.expires) {
+		return nil
+	}
+	if sess.accessToken != nil && !ipAllowed(r.RemoteAddr, sess.accessToken.IPRestrict) {
+		return nil
+	}
+	return sess
+}
+
+// ipAllowed returns true if the IP of the remote address matches any of the IP addresses
+// or CIDR ranges. Empty restrictions allow any IP.
+func ipAllowed(remoteAddr string, restrict []string) bool {
+	if len(restrict) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	for _, allowed := range restrict {
+		if _, ipnet, err := net.ParseCIDR(allowed); err == nil {
+			if ipnet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if ip.Equal(net.ParseIP(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionToken returns a unique token of a session or an access token. The caller must hold the lock.
+func (s *Server) sessionToken() string {
+	s.seq++
+	h := sha256.Sum256([]byte("session" + strconv.Itoa(s.seq)))
+	return "v2x" + hex.EncodeToString(h[:])
+}
+
+// login creates a session of the user if the password HMAC and OTP are valid.
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var p struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		OTP      string `json:"otp"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.users[p.Email]
+	if u == nil || !hmac.Equal([]byte(p.Password), []byte(u.passwordHMAC)) {
+		s.writeError(w, http.StatusUnauthorized, "invalid email or password")
+		return
+	}
+	switch p.OTP {
+	case OTP:
+	case "":
+		s.writeNeeds(w, "needsOTP", "needs otp")
+		return
+	default:
+		s.writeError(w, http.StatusUnauthorized, "incorrect otp")
+		return
+	}
+
+	token := s.sessionToken()
+	now := time.Now().UTC()
+	s.sessions[token] = &session{
+		user:    u,
+		created: now,
+		expires: now.Add(sessionDuration),
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   int(sessionDuration / time.Second),
+		"user":         u.User,
+	})
+}
+
+// logout deletes the session.
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.authenticate(r) == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	delete(s.sessions, token)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// getSession returns the session of the request.
+func (s *Server) getSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.authenticate(r)
+	if sess == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"session": sess.info(),
+	})
+}
+
+// unlock unlocks the session for the duration in seconds (10 minutes by default) if OTP is valid.
+func (s *Server) unlock(w http.ResponseWriter, r *http.Request) {
+	var p struct {
+		OTP      string `json:"otp"`
+		Duration int    `json:"duration"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if p.Duration < 0 {
+		s.writeError(w, http.StatusBadRequest, "duration must not be negative")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.authenticate(r)
+	if sess == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if p.OTP != OTP {
+		s.writeError(w, http.StatusUnauthorized, "incorrect otp")
+		return
+	}
+	d := defaultUnlockDuration
+	if p.Duration > 0 {
+		d = time.Duration(p.Duration) * time.Second
+	}
+	now := time.Now().UTC()
+	sess.unlock = &bitgo.SessionUnlock{Time: now, Expires: now.Add(d)}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"session": sess.info(),
+	})
+}
+
+// me returns the user of the session.
+func (s *Server) me(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.authenticate(r)
+	if sess == nil {
+		s.writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"user": sess.user.User,
+	})
+}
@@ -0,0 +1,381 @@
+package bitgotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// Defaults of the wallet API.
+const (
+	defaultWalletsLimit        = 25
+	defaultAddressesLimit      = 100
+	maxAddressesLimit          = 500
+	defaultTransactionsLimit   = 25
+	maxTransactionsLimit       = 500
+	defaultUnspentsLimit       = 100
+	defaultConsolidateLimit    = 85
+	defaultConsolidateFeeRate  = 10000
+	defaultNumUnspentsToMake   = 1
+	defaultMaxIterationCount   = 1
+	maxConsolidateInputs       = 200
+	consolidateTxOverheadBytes = 10
+	consolidateTxInputBytes    = 296
+	consolidateTxOutputBytes   = 32
+)
+
+// listWallets lists wallets sorted by ID and paginated with skip and limit.
+func (s *Server) listWallets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	skip, err := intParam(q.Get("skip"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid skip")
+		return
+	}
+	limit, err := intParam(q.Get("limit"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	if limit == 0 {
+		limit = defaultWalletsLimit
+	}
+
+	s.mu.Lock()
+	wallets := make([]bitgo.Wallet, 0, len(s.wallets))
+	for _, wlt := range s.wallets {
+		wallets = append(wallets, wlt.info())
+	}
+	s.mu.Unlock()
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].ID < wallets[j].ID
+	})
+
+	list := bitgo.WalletList{
+		ListMeta: bitgo.ListMeta{
+			Start: skip,
+			Total: len(wallets),
+		},
+		Wallets: []bitgo.Wallet{},
+	}
+	if skip < len(wallets) {
+		end := skip + limit
+		if end > len(wallets) {
+			end = len(wallets)
+		}
+		list.Wallets = wallets[skip:end]
+	}
+	list.Count = len(list.Wallets)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// getWallet returns the wallet with balances calculated from its unspents.
+func (s *Server) getWallet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	wlt := s.wallets[r.PathValue("id")]
+	var info bitgo.Wallet
+	if wlt != nil {
+		info = wlt.info()
+	}
+	s.mu.Unlock()
+
+	if wlt == nil {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// info returns the wallet as seen by the API. The caller must hold the lock.
+func (wlt *wallet) info() bitgo.Wallet {
+	info := bitgo.Wallet{
+		ID:               wlt.id,
+		Label:            wlt.id,
+		IsActive:         true,
+		Type:             "safehd",
+		Permissions:      "admin,spend,view",
+		AdminCount:       1,
+		PendingApprovals: []bitgo.PendingApproval{},
+	}
+	for _, a := range wlt.approvals {
+		if a.State == bitgo.ApprovalStatePending {
+			info.PendingApprovals = append(info.PendingApprovals, a.PendingApproval)
+		}
+	}
+	for _, u := range wlt.unspents {
+		info.Balance += u.Value
+		if u.Confirmations > 0 {
+			info.ConfirmedBalance += u.Value
+		}
+		if u.Confirmations > 0 || u.IsChange {
+			info.SpendableBalance += u.Value
+		}
+		if u.Instant {
+			info.InstantBalance += u.Value
+		}
+	}
+	return info
+}
+
+// intParam parses a non-negative integer query param, an empty param is zero.
+func intParam(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+// unspents lists wallet unspents filtered by minConfirms, minSize, target, segwit and instant
+// query params and paginated with skip and limit.
+func (s *Server) unspents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var p struct {
+		minConfirms, minSize, target, skip, limit int64
+	}
+	for name, v := range map[string]*int64{
+		"minConfirms": &p.minConfirms,
+		"minSize":     &p.minSize,
+		"target":      &p.target,
+		"skip":        &p.skip,
+		"limit":       &p.limit,
+	} {
+		if q.Get(name) == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(q.Get(name), 10, 64)
+		if err != nil || n < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid "+name)
+			return
+		}
+		*v = n
+	}
+	if p.limit == 0 {
+		p.limit = defaultUnspentsLimit
+	}
+	if p.limit > bitgo.MaxUnspentsLimit {
+		s.writeError(w, http.StatusBadRequest, "limit must be at most 250")
+		return
+	}
+	segwit := q.Get("segwit") != "false"
+	instant := q.Get("instant") == "true"
+
+	unspents, ok := s.walletUnspents(r.PathValue("id"))
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	var matched []bitgo.Unspent
+	var sum bitgo.Amount
+	for _, u := range unspents {
+		switch {
+		case int64(u.Confirmations) < p.minConfirms:
+			continue
+		case int64(u.Value) < p.minSize:
+			continue
+		case !segwit && isSegwit(u):
+			continue
+		case instant && !u.Instant:
+			continue
+		}
+		matched = append(matched, u)
+		// The API returns just enough unspents to accumulate to the target.
+		if sum += u.Value; p.target > 0 && int64(sum) >= p.target {
+			break
+		}
+	}
+
+	list := bitgo.UnspentList{
+		ListMeta: bitgo.ListMeta{
+			Start: int(p.skip),
+			Total: len(matched),
+		},
+		Unspents: []bitgo.Unspent{},
+	}
+	if p.skip < int64(len(matched)) {
+		end := p.skip + p.limit
+		if end > int64(len(matched)) {
+			end = int64(len(matched))
+		}
+		list.Unspents = matched[p.skip:end]
+	}
+	list.Count = len(list.Unspents)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// walletUnspents returns a copy of the wallet's unspents.
+func (s *Server) walletUnspents(walletID string) ([]bitgo.Unspent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wlt := s.wallets[walletID]
+	if wlt == nil {
+		return nil, false
+	}
+	return append([]bitgo.Unspent(nil), wlt.unspents...), true
+}
+
+// isSegwit returns true if the unspent belongs to a SegWit chain, see bitgo.Chain.IsSegwit.
+func isSegwit(u bitgo.Unspent) bool {
+	chain, _ := chainIndex(u.ChainPath)
+	return bitgo.Chain(chain).IsSegwit()
+}
+
+// consolidate spends wallet unspents selected by minConfirms, minSize and maxSize
+// into target number of new unspents in each of maxIterationCount transactions.
+func (s *Server) consolidate(w http.ResponseWriter, r *http.Request) {
+	if !s.checkUnlock(w, r) {
+		return
+	}
+	var p bitgo.WalletConsolidateParams
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	if tt, ok := s.consolidateUnspents(w, r.PathValue("id"), &p); ok {
+		writeJSON(w, http.StatusOK, tt)
+	}
+}
+
+// consolidateUnspents applies API defaults to the params and consolidates the wallet unspents.
+// It writes an error and returns false if nothing was consolidated.
+func (s *Server) consolidateUnspents(w http.ResponseWriter, walletID string, p *bitgo.WalletConsolidateParams) ([]bitgo.TxInfo, bool) {
+	if p.NumUnspentsToMake == 0 {
+		p.NumUnspentsToMake = defaultNumUnspentsToMake
+	}
+	if p.Limit == 0 {
+		p.Limit = defaultConsolidateLimit
+	}
+	if p.MaxIter == 0 {
+		p.MaxIter = defaultMaxIterationCount
+	}
+	if p.FeeRate == 0 {
+		p.FeeRate = defaultConsolidateFeeRate
+	}
+	if p.Limit < 2 || p.Limit > maxConsolidateInputs {
+		s.writeError(w, http.StatusBadRequest, "maxInputCountPerConsolidation must be between 2 and 200")
+		return nil, false
+	}
+
+	s.mu.Lock()
+	wlt := s.wallets[walletID]
+	if wlt == nil {
+		s.mu.Unlock()
+		s.writeError(w, http.StatusNotFound, "wallet not found")
+		return nil, false
+	}
+	if p.WalletPassphrase != wlt.passphrase {
+		s.mu.Unlock()
+		s.writeError(w, http.StatusBadRequest, "Unable to decrypt user keychain")
+		return nil, false
+	}
+
+	var tt []bitgo.TxInfo
+	for i := 0; i < p.MaxIter; i++ {
+		tx, ok := s.consolidateOnce(wlt, p)
+		if !ok {
+			break
+		}
+		tt = append(tt, tx)
+	}
+	s.mu.Unlock()
+
+	if len(tt) == 0 {
+		s.writeError(w, http.StatusBadRequest, "Fewer than 2 unspents available for consolidation")
+		return nil, false
+	}
+	return tt, true
+}
+
+// consolidateOnce creates a consolidation transaction which removes selected unspents
+// and adds new ones. The caller must hold the lock.
+func (s *Server) consolidateOnce(wlt *wallet, p *bitgo.WalletConsolidateParams) (bitgo.TxInfo, bool) {
+	var inputs []int
+	var sum bitgo.Amount
+	for i, u := range wlt.unspents {
+		switch {
+		case u.Confirmations < p.MinConfirms:
+			continue
+		case u.Value < p.MinValue:
+			continue
+		case p.MaxValue > 0 && u.Value > p.MaxValue:
+			continue
+		}
+		inputs = append(inputs, i)
+		sum += u.Value
+		if len(inputs) == p.Limit {
+			break
+		}
+	}
+	if len(inputs) < 2 || len(inputs) <= p.NumUnspentsToMake {
+		return bitgo.TxInfo{}, false
+	}
+
+	size := consolidateTxOverheadBytes + consolidateTxInputBytes*len(inputs) + consolidateTxOutputBytes*p.NumUnspentsToMake
+	fee := bitgo.Amount(int64(p.FeeRate) * int64(size) / 1000)
+	if sum-fee < bitgo.Amount(p.NumUnspentsToMake) {
+		return bitgo.TxInfo{}, false
+	}
+
+	history := bitgo.Transaction{
+		Date:    time.Now().UTC(),
+		Fee:     fee,
+		Pending: true,
+	}
+	for _, j := range inputs {
+		u := wlt.unspents[j]
+		history.Inputs = append(history.Inputs, bitgo.TxInput{PreviousHash: u.TxHash, PreviousOutputIndex: u.TxOutputN})
+		history.Entries = append(history.Entries, bitgo.TxEntry{Account: u.Address, Value: -u.Value})
+	}
+	// Inputs are removed from the end, so the indexes stay valid.
+	for i := len(inputs) - 1; i >= 0; i-- {
+		j := inputs[i]
+		wlt.unspents = append(wlt.unspents[:j], wlt.unspents[j+1:]...)
+	}
+
+	tx := bitgo.TxInfo{
+		TxID:        s.txHash(),
+		Status:      "accepted",
+		Fee:         fee,
+		TravelInfos: []bitgo.TravelInfo{},
+	}
+	tx.Tx = "01000000" + tx.TxID
+	value := (sum - fee) / bitgo.Amount(p.NumUnspentsToMake)
+	for n := 0; n < p.NumUnspentsToMake; n++ {
+		u := bitgo.Unspent{
+			TxHash:    tx.TxID,
+			TxOutputN: n,
+			Value:     value,
+			ChainPath: "/1/0",
+			IsChange:  true,
+			Wallet:    wlt.id,
+		}
+		// The remainder of the division goes to the first output.
+		if n == 0 {
+			u.Value += (sum - fee) % bitgo.Amount(p.NumUnspentsToMake)
+		}
+		wlt.unspents = append(wlt.unspents, u)
+		history.Outputs = append(history.Outputs, bitgo.TxOutput{
+			Vout:    n,
+			Value:   u.Value,
+			IsMine:  true,
+			Chain:   bitgo.ChainP2SHChange,
+			Account: u.Address,
+		})
+	}
+	history.ID = tx.TxID
+	history.Hex = tx.Tx
+	wlt.transactions = append(wlt.transactions, history)
+	return tx, true
+}
@@ -0,0 +1,108 @@
+package bitgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// defaultMaxResponseSize is a maximum size of a response body, see WithMaxResponseSize.
+const defaultMaxResponseSize = 32 << 20
+
+// ErrResponseTooLarge is returned when a response body exceeds the maximum size
+// configured with WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("bitgo: response body too large")
+
+// maxPooledBufferSize prevents occasional huge responses from pinning memory in the buffer pool.
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool reuses buffers to read response bodies.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// limitBody returns a body which fails with ErrResponseTooLarge
+// once more than n bytes were read from rc.
+func limitBody(rc io.ReadCloser, n int64) io.ReadCloser {
+	return &limitedBody{rc: rc, n: n}
+}
+
+type limitedBody struct {
+	rc io.ReadCloser
+	// n is a number of bytes which can still be read.
+	n int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.n < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// One extra byte is read to find out whether the body exceeds the limit.
+	if int64(len(p)) > b.n+1 {
+		p = p[:b.n+1]
+	}
+	n, err := b.rc.Read(p)
+	b.n -= int64(n)
+	if b.n < 0 {
+		return n + int(b.n), ErrResponseTooLarge
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.rc.Close()
+}
+
+// decodeBody buffers the whole body and decodes JSON from it into v.
+// The body is read into a pooled buffer rather than with json.Decoder,
+// because the decoder grows its own buffer to the size of the body on every call.
+// The body is expected to be limited with limitBody, so reading fails with ErrResponseTooLarge
+// as soon as the limit is exceeded.
+func decodeBody(body io.Reader, v interface{}) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(body); err != nil {
+		return err
+	}
+	return json.Unmarshal(buf.Bytes(), v)
+}
+
+// maxErrorBodySize is a maximum size of an error response body kept in Error.Body.
+const maxErrorBodySize = 64 << 10
+
+// readErrorBody reads at most maxErrorBodySize bytes of an error response body.
+// A body which is too large is truncated rather than failing, so the error can still be classified.
+func readErrorBody(body io.Reader) ([]byte, error) {
+	b, err := readBody(io.LimitReader(body, maxErrorBodySize))
+	if errors.Is(err, ErrResponseTooLarge) {
+		err = nil
+	}
+	return b, err
+}
+
+// readBody reads the whole body using a pooled buffer and returns its copy.
+func readBody(body io.Reader) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	_, err := buf.ReadFrom(body)
+	b := make([]byte, buf.Len())
+	copy(b, buf.Bytes())
+	return b, err
+}
@@ -0,0 +1,176 @@
+package bitgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CreateTransactionParams represents BitGo Express parameters used when building a transaction.
+// For more details, see https://bitgo.github.io/bitgo-docs/#create-transaction.
+type CreateTransactionParams struct {
+	// The destination addresses and amounts.
+	Recipients []Recipient `json:"recipients"`
+	// The desired fee rate for the transaction in satoshis/kilobyte.
+	FeeRate FeeRate `json:"feeRate,omitempty"`
+	// Only choose unspent inputs with at least this many confirmations.
+	MinConfirms int `json:"minConfirms,omitempty"`
+	// Whether MinConfirms also applies to change outputs of previous transactions of the wallet.
+	// Nil means the API default is used.
+	EnforceMinConfirmsForChange *bool `json:"enforceMinConfirmsForChange,omitempty"`
+	// The address to send the change to. By default a new change address of the wallet is created.
+	ChangeAddress string `json:"changeAddress,omitempty"`
+	// Whether to build a BitGo Instant transaction guaranteed against double spends.
+	Instant bool `json:"instant,omitempty"`
+}
+
+// Validate checks whether the params are accepted by the API.
+func (p *CreateTransactionParams) Validate() error {
+	if p == nil {
+		return errors.New("bitgo: create transaction params must not be nil")
+	}
+	return validateRecipients("create transaction", p.Recipients)
+}
+
+// UnsignedTx is a transaction built by BitGo Express which is not signed yet.
+// It can be validated or persisted before it's signed with SignTransaction.
+type UnsignedTx struct {
+	// The serialized unsigned transaction (in hex format).
+	TransactionHex string `json:"transactionHex"`
+	// The wallet unspents selected as transaction inputs.
+	Unspents []Unspent `json:"unspents"`
+	// The change outputs of the transaction.
+	ChangeAddresses []ChangeAddress `json:"changeAddresses"`
+	// Transaction fee in satoshis.
+	Fee Amount `json:"fee"`
+	// The fee rate of the transaction in satoshis/kilobyte.
+	FeeRate FeeRate `json:"feeRate"`
+	// The estimated size of the signed transaction in bytes.
+	EstimatedSize int `json:"estimatedSize"`
+	// The ID of the wallet the transaction spends from.
+	WalletID string `json:"walletId"`
+	// Travel rule information to be sent along with the transaction.
+	TravelInfos []TravelInfo `json:"travelInfos"`
+}
+
+// InputAmount returns the total amount of the transaction inputs.
+func (t *UnsignedTx) InputAmount() Amount {
+	var sum Amount
+	for _, u := range t.Unspents {
+		sum += u.Value
+	}
+	return sum
+}
+
+// ChangeAddress is a change output of a transaction which returns coins to the wallet.
+type ChangeAddress struct {
+	// The change address.
+	Address string `json:"address"`
+	// The BIP32 path of the address relative to the wallet, e.g., "/1/7".
+	Path string `json:"path"`
+	// The amount in satoshis returned to the wallet.
+	Amount Amount `json:"amount"`
+}
+
+// Keychain holds the wallet's private key used to sign transactions.
+type Keychain struct {
+	// The extended private key.
+	XPrv string `json:"xprv"`
+}
+
+// SignTransactionParams represents BitGo Express parameters used when signing a transaction.
+// For more details, see https://bitgo.github.io/bitgo-docs/#sign-transaction.
+type SignTransactionParams struct {
+	// The serialized unsigned transaction (in hex format).
+	TransactionHex string `json:"transactionHex"`
+	// The unspents spent by the transaction as returned by CreateTransaction.
+	Unspents []Unspent `json:"unspents"`
+	// The user keychain with the private key.
+	Keychain *Keychain `json:"keychain"`
+}
+
+// Validate checks whether the params are accepted by the API.
+func (p *SignTransactionParams) Validate() error {
+	switch {
+	case p == nil:
+		return errors.New("bitgo: sign transaction params must not be nil")
+	case p.TransactionHex == "":
+		return errors.New("bitgo: sign transaction hex must not be empty")
+	case len(p.Unspents) == 0:
+		return errors.New("bitgo: sign transaction unspents must not be empty")
+	case p.Keychain == nil || p.Keychain.XPrv == "":
+		return errors.New("bitgo: sign transaction keychain must have a private key")
+	}
+	return nil
+}
+
+// SignedTx is a transaction signed by BitGo Express which can be sent with SendTransaction.
+type SignedTx struct {
+	// The serialized half-signed transaction (in hex format).
+	Tx string `json:"tx"`
+}
+
+// SendTransactionParams represents API parameters used when sending a signed transaction.
+// For more details, see https://bitgo.github.io/bitgo-docs/#send-transaction.
+type SendTransactionParams struct {
+	// The serialized half-signed transaction (in hex format).
+	Tx string `json:"tx"`
+	// A unique ID of the transaction which prevents sending it twice.
+	SequenceID string `json:"sequenceId,omitempty"`
+	// A note attached to the transaction which is visible to wallet users.
+	Message string `json:"message,omitempty"`
+	// Whether to send a BitGo Instant transaction guaranteed against double spends.
+	Instant bool `json:"instant,omitempty"`
+	// One-time password of the user if the wallet policy requires it.
+	OTP string `json:"otp,omitempty"`
+}
+
+// CreateTransaction builds an unsigned transaction using BitGo Express.
+// The transaction isn't signed or sent, so it can be inspected first.
+func (s *walletService) CreateTransaction(ctx context.Context, walletID string, params *CreateTransactionParams) (*UnsignedTx, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("wallet/%s/createtransaction", walletID)
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var t UnsignedTx
+	if _, err = s.client.Do(req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SignTransaction signs the unsigned transaction using BitGo Express.
+func (s *walletService) SignTransaction(ctx context.Context, walletID string, params *SignTransactionParams) (*SignedTx, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("wallet/%s/signtransaction", walletID)
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var t SignedTx
+	if _, err = s.client.Do(req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SendTransaction broadcasts the signed transaction.
+// A transaction which requires approval isn't an error: the result's RequiresApproval returns true.
+func (s *walletService) SendTransaction(ctx context.Context, params *SendTransactionParams) (*SendResult, error) {
+	switch {
+	case params == nil:
+		return nil, errors.New("bitgo: send transaction params must not be nil")
+	case params.Tx == "":
+		return nil, errors.New("bitgo: send transaction tx must not be empty")
+	}
+	return s.send(ctx, "tx/send", params)
+}
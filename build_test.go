@@ -0,0 +1,131 @@
+package bitgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestCreateSignSendTransaction(t *testing.T) {
+	filename := filepath.Join("testdata", "createtransaction.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	bodies := make(map[string]map[string]interface{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		bodies[r.URL.Path] = body
+
+		switch r.URL.Path {
+		case "/api/v1/wallet/wallet/createtransaction":
+			w.Write(content)
+		case "/api/v1/wallet/wallet/signtransaction":
+			w.Write([]byte(`{"tx":"0100000001signed"}`))
+		default:
+			w.Write([]byte(`{"status":"accepted","tx":"0100000001signed","hash":"abc"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	ctx := context.Background()
+	unsigned, err := client.Wallet.CreateTransaction(ctx, "wallet", &bitgo.CreateTransactionParams{
+		Recipients: []bitgo.Recipient{{Address: "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm", Amount: 100000}},
+		FeeRate:    10000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsigned.Fee != 3730 || unsigned.FeeRate != 10000 || unsigned.InputAmount() != 200000 {
+		t.Errorf("unexpected unsigned transaction %#v", unsigned)
+	}
+	wantChange := []bitgo.ChangeAddress{{Address: "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4", Path: "/1/118", Amount: 96270}}
+	if !reflect.DeepEqual(unsigned.ChangeAddresses, wantChange) {
+		t.Errorf("expected change %#v, got %#v", wantChange, unsigned.ChangeAddresses)
+	}
+	if u := unsigned.Unspents[0]; u.ChainPath != "/1/117" || u.RedeemScript == "" {
+		t.Errorf("unexpected unspent %#v", u)
+	}
+
+	signed, err := client.Wallet.SignTransaction(ctx, "wallet", &bitgo.SignTransactionParams{
+		TransactionHex: unsigned.TransactionHex,
+		Unspents:       unsigned.Unspents,
+		Keychain:       &bitgo.Keychain{XPrv: "xprv9s21ZrQH143K"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.Wallet.SendTransaction(ctx, &bitgo.SendTransactionParams{
+		Tx:         signed.Tx,
+		SequenceID: "withdrawal-42",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TxID != "abc" || res.RequiresApproval() {
+		t.Errorf("unexpected result %#v", res)
+	}
+
+	wantPaths := []string{
+		"/api/v1/wallet/wallet/createtransaction",
+		"/api/v1/wallet/wallet/signtransaction",
+		"/api/v1/tx/send",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("expected paths %q, got %q", wantPaths, paths)
+	}
+	sign := bodies["/api/v1/wallet/wallet/signtransaction"]
+	if sign["transactionHex"] != unsigned.TransactionHex || len(sign["unspents"].([]interface{})) != 1 {
+		t.Errorf("unexpected sign body %v", sign)
+	}
+	if send := bodies["/api/v1/tx/send"]; send["tx"] != "0100000001signed" || send["sequenceId"] != "withdrawal-42" {
+		t.Errorf("unexpected send body %v", send)
+	}
+}
+
+func TestSignTransactionParamsValidate(t *testing.T) {
+	tests := []struct {
+		params bitgo.SignTransactionParams
+		valid  bool
+	}{
+		{bitgo.SignTransactionParams{TransactionHex: "01", Unspents: []bitgo.Unspent{{}}, Keychain: &bitgo.Keychain{XPrv: "xprv"}}, true},
+		{bitgo.SignTransactionParams{Unspents: []bitgo.Unspent{{}}, Keychain: &bitgo.Keychain{XPrv: "xprv"}}, false},
+		{bitgo.SignTransactionParams{TransactionHex: "01", Keychain: &bitgo.Keychain{XPrv: "xprv"}}, false},
+		{bitgo.SignTransactionParams{TransactionHex: "01", Unspents: []bitgo.Unspent{{}}}, false},
+	}
+	for _, test := range tests {
+		err := test.params.Validate()
+		if (err == nil) != test.valid {
+			t.Errorf("Validate(%#v) = %v, want valid %v", test.params, err, test.valid)
+		}
+	}
+}
+
+func TestTransactionNilParams(t *testing.T) {
+	client := bitgo.NewClient(bitgo.WithBaseURL("http://0.0.0.0:1"))
+	ctx := context.Background()
+	if _, err := client.Wallet.CreateTransaction(ctx, "", nil); err == nil {
+		t.Error("expected validation error for nil create transaction params")
+	}
+	if _, err := client.Wallet.SignTransaction(ctx, "", nil); err == nil {
+		t.Error("expected validation error for nil sign transaction params")
+	}
+	if _, err := client.Wallet.SendTransaction(ctx, nil); err == nil {
+		t.Error("expected validation error for nil send transaction params")
+	}
+}
@@ -20,9 +20,12 @@ func main() {
 	walletPassphrase := flag.String("passphrase", "", "Passphrase of the wallet.")
 	numUnspentsToMake := flag.Int("target", 1, "Number of outputs created by the consolidation transaction.")
 	limit := flag.Int("limit", 85, "Number of unspents to select.")
-	minValue := flag.Float64("min-value", 0, "Ignore unspents smaller than this amount of bitcoins.")
-	maxValue := flag.Float64("max-value", 0, "Ignore unspents larger than this amount of bitcoins.")
-	feeRate := flag.Int("fee-rate", 0, "The desired fee rate for the transaction in satoshis/kilobyte.")
+	var minValue, maxValue bitgo.Amount
+	flag.Var(&minValue, "min-value", "Ignore unspents smaller than this amount of bitcoins, e.g., 0.001 or \"1500 sat\".")
+	flag.Var(&maxValue, "max-value", "Ignore unspents larger than this amount of bitcoins, e.g., 0.001 or \"1 mBTC\".")
+	var feeRate bitgo.FeeRate
+	flag.Var(&feeRate, "fee-rate", "The desired fee rate for the transaction in satoshis/kilobyte, e.g., 10000 or \"12.5 sat/vB\".")
+	feeBlocks := flag.Int("fee-blocks", 0, "Estimate the fee rate to confirm within this number of blocks if -fee-rate isn't set.")
 	minConfirms := flag.Int("min-confirms", 0, "The required number of confirmations for each transaction input.")
 	maxIter := flag.Int("max-iter", 1, "Maximum number of consolidation iterations to perform.")
 	flag.Parse()
@@ -43,15 +46,24 @@ func main() {
 		bitgo.WithBaseURL(*baseURL),
 		bitgo.WithAccesToken(*accessToken),
 	)
+	if feeRate == 0 && *feeBlocks > 0 {
+		fee, err := client.Fee.Estimate(ctx, *feeBlocks)
+		if err != nil {
+			log.Fatalf("consolidate: failed to estimate fee rate: %v", err)
+		}
+		feeRate = fee.FeePerKB
+		log.Printf("consolidate: estimated fee rate %s to confirm within %d blocks", feeRate, *feeBlocks)
+	}
+
 	params := &bitgo.WalletConsolidateParams{
 		NumUnspentsToMake: *numUnspentsToMake,
 		Limit:             *limit,
 		MinConfirms:       *minConfirms,
 		WalletPassphrase:  *walletPassphrase,
-		MinValue:          bitgo.ToSatoshis(*minValue),
-		MaxValue:          bitgo.ToSatoshis(*maxValue),
+		MinValue:          minValue,
+		MaxValue:          maxValue,
 		MaxIter:           *maxIter,
-		FeeRate:           *feeRate,
+		FeeRate:           feeRate,
 	}
 	tt, err := client.Wallet.Consolidate(ctx, *walletID, params)
 
@@ -6,7 +6,6 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
@@ -19,13 +18,16 @@ func main() {
 	baseURL := flag.String("host", "http://0.0.0.0:3080", "BitGo API server base URL.")
 	accessToken := flag.String("token", "", "BitGo access token.")
 	walletID := flag.String("wallet", "", "BitGo wallet ID (BTC address).")
-	target := flag.Float64("target", 0, "The API will attempt to return enough unspents to accumulate to at least this amount of bitcoins.")
-	minConfirms := flag.String("min-confirms", "", "Only include unspents with at least this many confirmations.")
-	minSize := flag.Float64("min-size", 0, "Only include unspents that are at least this many bitcoins.")
-	limit := flag.String("limit", "", "Max number of results to return in a single call (default=100, max=250).")
-	skip := flag.String("skip", "", "The starting index number to list from. Default is 0.")
+	var target, minSize bitgo.Amount
+	flag.Var(&target, "target", "The API will attempt to return enough unspents to accumulate to at least this amount of bitcoins, e.g., 0.29 or \"290 mBTC\".")
+	minConfirms := flag.Int("min-confirms", 0, "Only include unspents with at least this many confirmations.")
+	flag.Var(&minSize, "min-size", "Only include unspents that are at least this many bitcoins, e.g., 0.001 or \"1500 sat\".")
+	limit := flag.Int("limit", 0, "Max number of results to return in a single call (default=100, max=250).")
+	skip := flag.Int("skip", 0, "The starting index number to list from. Default is 0.")
 	segwit := flag.Bool("segwit", true, "Include SegWit unspents.")
-	waitSeconds := flag.Int("wait", 15, "How many seconds to wait after failed download attempt.")
+	workers := flag.Int("workers", 1, "How many pages of unspents to download concurrently.")
+	attempts := flag.Int("attempts", 10, "How many times to try to download a page of unspents.")
+	waitSeconds := flag.Int("wait", 15, "Max number of seconds to wait after failed download attempt.")
 	flag.Parse()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -43,55 +45,63 @@ func main() {
 	client := bitgo.NewClient(
 		bitgo.WithBaseURL(*baseURL),
 		bitgo.WithAccesToken(*accessToken),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: *attempts,
+			MinBackoff:  time.Second,
+			MaxBackoff:  time.Duration(*waitSeconds) * time.Second,
+			OnRetry: func(a bitgo.RetryAttempt) {
+				log.Printf("utxo: attempt %d failed to list unspents: %v", a.Attempt, a.Err)
+				log.Printf("utxo: retrying in %s...", a.Wait)
+			},
+		}),
 	)
 
-	params := url.Values{}
-	if *target > 0 {
-		params.Set("target", fmt.Sprintf("%d", bitgo.ToSatoshis(*target)))
+	params := &bitgo.UnspentsParams{
+		Target:      target,
+		MinConfirms: *minConfirms,
+		MinSize:     minSize,
+		Limit:       *limit,
+		Skip:        *skip,
+		Segwit:      segwit,
 	}
-	if *minConfirms != "" {
-		params.Set("minConfirms", *minConfirms)
-	}
-	if *minSize > 0 {
-		params.Set("minSize", fmt.Sprintf("%d", bitgo.ToSatoshis(*minSize)))
-	}
-	if *limit != "" {
-		params.Set("limit", *limit)
-	}
-	if *skip != "" {
-		params.Set("skip", *skip)
-	}
-	if *segwit {
-		params.Set("segwit", "true")
-	} else {
-		params.Set("segwit", "false")
+	if err := params.Validate(); err != nil {
+		log.Fatalf("utxo: %v", err)
 	}
 
-	downloaded := 0
-	for {
-		err := client.Wallet.Unspents(ctx, *walletID, params, func(list *bitgo.UnspentList) {
-			downloaded = list.Start + list.Count
-			log.Printf("utxo: fetched %d/%d unspents", downloaded, list.Total)
+	// Unspents can shift between pages while they are downloaded, so duplicates are skipped.
+	seen := make(map[string]bool)
+	var count int
+	printPage := func(page *bitgo.Page[bitgo.Unspent]) error {
+		log.Printf("utxo: fetched %d/%d unspents", page.Start+page.Count, page.Total)
 
-			for _, utxo := range list.Unspents {
-				fmt.Printf("%0.8f\n", bitgo.ToBitcoins(utxo.Value))
+		for _, utxo := range page.Items {
+			if seen[utxo.OutPoint()] {
+				continue
 			}
-		})
-		// Stop when we downloaded everything without errors or
-		// when a context was cancelled (user hit Ctrl+C).
-		if err == nil || ctx.Err() != nil {
-			break
+			seen[utxo.OutPoint()] = true
+			count++
+			fmt.Printf("%s\n", utxo.Value.Format(bitgo.UnitBTC))
 		}
-
+		return nil
+	}
+	opts := bitgo.FetchOptions[bitgo.Unspent]{
+		Workers: *workers,
+	}
+	pager := client.Wallet.UnspentsPager(*walletID, params)
+	err := pager.FetchConcurrently(ctx, opts, printPage)
+	// The unspents which were added while the pages were downloaded are fetched one page at a time.
+	for err == nil && pager.Next(ctx) {
+		err = printPage(pager.Page())
+	}
+	if err == nil {
+		err = pager.Err()
+	}
+	// A context is cancelled when user hit Ctrl+C.
+	if err != nil && ctx.Err() == nil {
 		if apiErr, ok := err.(bitgo.Error); ok {
-			log.Printf("utxo: failed to list unspents, %d: %v", apiErr.HTTPStatusCode, apiErr)
-		} else {
-			log.Printf("utxo: failed to list unspents: %v", err)
+			log.Fatalf("utxo: failed to list unspents, %d: %v", apiErr.HTTPStatusCode, apiErr)
 		}
-
-		// We shall wait a bit and then try again.
-		log.Printf("utxo: retrying in %d seconds...", *waitSeconds)
-		time.Sleep(time.Duration(*waitSeconds) * time.Second)
-		params.Set("skip", fmt.Sprintf("%d", downloaded))
+		log.Fatalf("utxo: failed to list unspents: %v", err)
 	}
+	log.Printf("utxo: listed %d unspents", count)
 }
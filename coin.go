@@ -0,0 +1,300 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Coin gives access to coin-scoped endpoints of BitGo API v2, e.g., /api/v2/tbtc/wallet/{id}.
+// Responses are mapped onto API v1 types where their semantics match.
+type Coin struct {
+	// Name is the coin ticker, e.g., "btc" or "tbtc".
+	Name string
+
+	Wallet CoinWalletService
+}
+
+// Coin returns API v2 services of the coin, e.g., client.Coin("tbtc").Wallet.
+// The same Coin is returned for the same name, so its services can be replaced, e.g., with mocks.
+func (c *Client) Coin(name string) *Coin {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if coin, ok := c.coins[name]; ok {
+		return coin
+	}
+	if c.coins == nil {
+		c.coins = make(map[string]*Coin)
+	}
+	coin := Coin{
+		Name:   name,
+		Wallet: &coinWalletService{client: c, coin: name},
+	}
+	c.coins[name] = &coin
+	return &coin
+}
+
+// CoinWalletService communicates with the coin-scoped wallet API v2 endpoints.
+// See bitgomock package for its mock implementation.
+type CoinWalletService interface {
+	// Get gets a wallet by its ID.
+	Get(ctx context.Context, walletID string) (*Wallet, error)
+	// UnspentsPager returns a Pager to iterate over unspent transaction outputs (UTXOs) of a wallet.
+	UnspentsPager(walletID string, params *UnspentsParams) *Pager[Unspent]
+	// Consolidate coalesces UTXOs of a wallet to a smaller number with a single transaction.
+	Consolidate(ctx context.Context, walletID string, bodyParams *WalletConsolidateParams) (*TxInfo, error)
+}
+
+// coinWalletService communicates with the coin-scoped wallet API v2 endpoints.
+type coinWalletService struct {
+	client *Client
+	coin   string
+}
+
+var _ CoinWalletService = (*coinWalletService)(nil)
+
+// coinWallet is a wallet as returned by API v2.
+type coinWallet struct {
+	ID               string            `json:"id"`
+	Label            string            `json:"label"`
+	Type             string            `json:"type"`
+	Deleted          bool              `json:"deleted"`
+	Balance          Amount            `json:"balance"`
+	ConfirmedBalance Amount            `json:"confirmedBalance"`
+	SpendableBalance Amount            `json:"spendableBalance"`
+	Admin            WalletAdmin       `json:"admin"`
+	PendingApprovals []PendingApproval `json:"pendingApprovals"`
+	Users            []struct {
+		User        string   `json:"user"`
+		Permissions []string `json:"permissions"`
+	} `json:"users"`
+}
+
+// wallet maps the API v2 wallet onto Wallet. Its type is "hot", "cold" or "custodial" rather than "safehd".
+func (w *coinWallet) wallet() *Wallet {
+	wlt := Wallet{
+		ID:               w.ID,
+		Label:            w.Label,
+		IsActive:         !w.Deleted,
+		Type:             w.Type,
+		Balance:          w.Balance,
+		ConfirmedBalance: w.ConfirmedBalance,
+		SpendableBalance: w.SpendableBalance,
+		Admin:            w.Admin,
+		PendingApprovals: w.PendingApprovals,
+	}
+	for _, u := range w.Users {
+		for _, p := range u.Permissions {
+			if p == "admin" {
+				wlt.AdminCount++
+			}
+		}
+	}
+	return &wlt
+}
+
+// Get gets a wallet by its ID. API v2 wallets don't have Permissions and InstantBalance fields,
+// and the wallet ID isn't its first receive address.
+func (s *coinWalletService) Get(ctx context.Context, walletID string) (*Wallet, error) {
+	path := fmt.Sprintf("wallet/%s", walletID)
+	req, err := s.client.NewCoinRequest(ctx, s.coin, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var w coinWallet
+	if _, err = s.client.Do(req, &w); err != nil {
+		return nil, err
+	}
+	return w.wallet(), nil
+}
+
+// coinUnspent is an unspent as returned by API v2.
+type coinUnspent struct {
+	// The ID of the unspent is its outpoint, e.g., "3246b59f...5ce6:0".
+	ID            string    `json:"id"`
+	Address       string    `json:"address"`
+	Value         Amount    `json:"value"`
+	BlockHeight   int       `json:"blockHeight"`
+	Date          time.Time `json:"date"`
+	Wallet        string    `json:"wallet"`
+	Chain         int       `json:"chain"`
+	Index         int       `json:"index"`
+	RedeemScript  string    `json:"redeemScript"`
+	WitnessScript string    `json:"witnessScript"`
+}
+
+// unspent maps the API v2 unspent onto Unspent. Change unspents are on odd chains, e.g., 1 or 11.
+func (u *coinUnspent) unspent(raw json.RawMessage) (Unspent, error) {
+	hash, vout, ok := strings.Cut(u.ID, ":")
+	n, err := strconv.Atoi(vout)
+	if !ok || err != nil {
+		return Unspent{}, fmt.Errorf("bitgo: unspent id must be an outpoint, got %q", u.ID)
+	}
+	return Unspent{
+		Address:       u.Address,
+		TxHash:        hash,
+		TxOutputN:     n,
+		Value:         u.Value,
+		RedeemScript:  u.RedeemScript,
+		WitnessScript: u.WitnessScript,
+		ChainPath:     fmt.Sprintf("/%d/%d", u.Chain, u.Index),
+		IsChange:      u.Chain%2 == 1,
+		BlockHeight:   u.BlockHeight,
+		Date:          u.Date,
+		Wallet:        u.Wallet,
+		Raw:           raw,
+	}, nil
+}
+
+// coinUnspentList is a list of unspents as returned by API v2.
+// It's paginated with the ID of the last unspent instead of skip.
+type coinUnspentList struct {
+	Unspents        []json.RawMessage `json:"unspents"`
+	NextBatchPrevID string            `json:"nextBatchPrevId"`
+}
+
+// coinUnspentsValues returns the params encoded as API v2 query string, zero values are omitted.
+func coinUnspentsValues(p *UnspentsParams, prevID string) url.Values {
+	v := url.Values{}
+	if p.MinConfirms > 0 {
+		v.Set("minConfirms", strconv.Itoa(p.MinConfirms))
+	}
+	if p.MinSize > 0 {
+		v.Set("minValue", strconv.FormatInt(int64(p.MinSize), 10))
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Segwit != nil {
+		v.Set("segwit", strconv.FormatBool(*p.Segwit))
+	}
+	if prevID != "" {
+		v.Set("prevId", prevID)
+	}
+	return v
+}
+
+// UnspentsPager returns a Pager to iterate over unspent transaction outputs (UTXOs) of a wallet.
+// You can filter unspents using params, nil params means API defaults are used.
+// API v2 doesn't support Target, Skip and Instant params, and it doesn't return the number of confirmations.
+// The total number of unspents isn't known either, so Page.Total only shows whether there are more pages.
+// Pages are fetched with a cursor, so pagination can't be resumed from Pager.Offset,
+// and Pager.FetchConcurrently fetches them one after another.
+func (s *coinWalletService) UnspentsPager(walletID string, params *UnspentsParams) *Pager[Unspent] {
+	path := fmt.Sprintf("wallet/%s/unspents", walletID)
+	var p UnspentsParams
+	if params != nil {
+		p = *params
+	}
+	var prevID string
+
+	return newCursorPager(func(ctx context.Context, skip int) (*Page[Unspent], error) {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		if p.Target != 0 || p.Skip != 0 || p.Instant {
+			return nil, errors.New("bitgo: unspents target, skip and instant params are not supported by API v2")
+		}
+		req, err := s.client.NewCoinRequest(ctx, s.coin, http.MethodGet, path, coinUnspentsValues(&p, prevID), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var v coinUnspentList
+		if _, err = s.client.Do(req, &v); err != nil {
+			return nil, err
+		}
+		page := Page[Unspent]{
+			ListMeta: ListMeta{
+				Count: len(v.Unspents),
+				Start: skip,
+				Total: skip + len(v.Unspents),
+			},
+			Items: make([]Unspent, 0, len(v.Unspents)),
+		}
+		for _, raw := range v.Unspents {
+			var cu coinUnspent
+			if err = json.Unmarshal(raw, &cu); err != nil {
+				return nil, err
+			}
+			u, err := cu.unspent(raw)
+			if err != nil {
+				return nil, err
+			}
+			page.Items = append(page.Items, u)
+		}
+		// The next batch starts after the last unspent, so there are more pages than the ones fetched.
+		if prevID = v.NextBatchPrevID; prevID != "" {
+			page.Total++
+		}
+		return &page, nil
+	})
+}
+
+// coinTxInfo is a response we get from API v2 consolidateUnspents endpoint.
+type coinTxInfo struct {
+	TxID     string `json:"txid"`
+	Tx       string `json:"tx"`
+	Status   string `json:"status"`
+	Transfer struct {
+		Fee Amount `json:"fee"`
+	} `json:"transfer"`
+}
+
+// Consolidate coalesces UTXOs of a wallet to a smaller number with a single transaction.
+// API v2 doesn't support more than one consolidation iteration, see WalletConsolidateParams.MaxIter.
+func (s *coinWalletService) Consolidate(ctx context.Context, walletID string, bodyParams *WalletConsolidateParams) (*TxInfo, error) {
+	var p WalletConsolidateParams
+	if bodyParams != nil {
+		p = *bodyParams
+	}
+	if p.MaxIter > 1 {
+		return nil, fmt.Errorf("bitgo: consolidation max iterations are not supported by API v2, got %d", p.MaxIter)
+	}
+	body := struct {
+		WalletPassphrase  string  `json:"walletPassphrase,omitempty"`
+		NumUnspentsToMake int     `json:"numUnspentsToMake,omitempty"`
+		Limit             int     `json:"limit,omitempty"`
+		MinConfirms       int     `json:"minConfirms,omitempty"`
+		MinValue          Amount  `json:"minValue,omitempty"`
+		MaxValue          Amount  `json:"maxValue,omitempty"`
+		FeeRate           FeeRate `json:"feeRate,omitempty"`
+	}{
+		WalletPassphrase:  p.WalletPassphrase,
+		NumUnspentsToMake: p.NumUnspentsToMake,
+		Limit:             p.Limit,
+		MinConfirms:       p.MinConfirms,
+		MinValue:          p.MinValue,
+		MaxValue:          p.MaxValue,
+		FeeRate:           p.FeeRate,
+	}
+	path := fmt.Sprintf("wallet/%s/consolidateUnspents", walletID)
+	req, err := s.client.NewCoinRequest(ctx, s.coin, http.MethodPost, path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if _, err = s.client.Do(req, &raw); err != nil {
+		return nil, err
+	}
+	var t coinTxInfo
+	if err = json.Unmarshal(raw, &t); err != nil {
+		return nil, err
+	}
+	return &TxInfo{
+		TxID:   t.TxID,
+		Tx:     t.Tx,
+		Status: t.Status,
+		Fee:    t.Transfer.Fee,
+		Raw:    raw,
+	}, nil
+}
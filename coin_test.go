@@ -0,0 +1,248 @@
+package bitgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestCoinWalletGet(t *testing.T) {
+	wallet, err := ioutil.ReadFile(filepath.Join("testdata", "coinwallet.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tbtc/wallet/59cd72485007a239fb00282ed480da1f" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write(wallet)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	got, err := client.Coin("tbtc").Wallet.Get(context.Background(), "59cd72485007a239fb00282ed480da1f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bitgo.Wallet{
+		ID:               "59cd72485007a239fb00282ed480da1f",
+		Label:            "payouts",
+		IsActive:         true,
+		Type:             "hot",
+		AdminCount:       1,
+		Balance:          2500000,
+		ConfirmedBalance: 2000000,
+		SpendableBalance: 2000000,
+		PendingApprovals: []bitgo.PendingApproval{},
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("should be %#v, not %#v", want, *got)
+	}
+}
+
+func TestCoinWalletUnspents(t *testing.T) {
+	unspents, err := ioutil.ReadFile(filepath.Join("testdata", "coinunspents.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tbtc/wallet/59cd72485007a239fb00282ed480da1f/unspents" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("prevId") != "" {
+			w.Write([]byte(`{"coin":"tbtc","unspents":[]}`))
+			return
+		}
+		w.Write(unspents)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	p := client.Coin("tbtc").Wallet.UnspentsPager("59cd72485007a239fb00282ed480da1f", &bitgo.UnspentsParams{
+		MinConfirms: 1,
+		MinSize:     1000,
+		Limit:       2,
+	})
+	var got []bitgo.Unspent
+	for u, err := range p.All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(u.Raw) == 0 {
+			t.Errorf("expected raw JSON of %s to be kept", u.OutPoint())
+		}
+		u.Raw = nil
+		got = append(got, u)
+	}
+
+	wantQueries := []string{
+		"limit=2&minConfirms=1&minValue=1000",
+		"limit=2&minConfirms=1&minValue=1000&prevId=7c1f2e3d4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0%3A1",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("expected %v queries, got %v", wantQueries, queries)
+	}
+
+	var raw struct {
+		Unspents []struct {
+			RedeemScript  string `json:"redeemScript"`
+			WitnessScript string `json:"witnessScript"`
+		} `json:"unspents"`
+	}
+	if err = json.Unmarshal(unspents, &raw); err != nil {
+		t.Fatal(err)
+	}
+	want := []bitgo.Unspent{
+		{
+			Address:      "2MsyDpNX7wmvbFqEtLBLbFrsPkNCzgbBcBE",
+			TxHash:       "3246b59fcec99d5c31d5a4a8b4a2e3b9e1c9a2b1f2e4d6c8a0b1c2d3e4f5ce6",
+			TxOutputN:    0,
+			Value:        1500000,
+			RedeemScript: raw.Unspents[0].RedeemScript,
+			ChainPath:    "/0/3",
+			BlockHeight:  1326142,
+			Date:         time.Date(2018, 5, 21, 11, 46, 13, 0, time.UTC),
+			Wallet:       "59cd72485007a239fb00282ed480da1f",
+		},
+		{
+			Address:       "2N3X7gH3iC7UZVTqWa1yVq3nyrHsjgsnYkd",
+			TxHash:        "7c1f2e3d4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0",
+			TxOutputN:     1,
+			Value:         500000,
+			RedeemScript:  raw.Unspents[1].RedeemScript,
+			WitnessScript: raw.Unspents[1].WitnessScript,
+			ChainPath:     "/11/0",
+			IsChange:      true,
+			BlockHeight:   1326150,
+			Date:          time.Date(2018, 5, 21, 12, 30, 2, 0, time.UTC),
+			Wallet:        "59cd72485007a239fb00282ed480da1f",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("should be %#v, not %#v", want, got)
+	}
+}
+
+func TestCoinWalletUnspentsFetchConcurrently(t *testing.T) {
+	const total = 7
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// The next batch starts after the unspent with prevId, e.g., "2:0".
+		start := 0
+		if prevID := r.URL.Query().Get("prevId"); prevID != "" {
+			fmt.Sscanf(prevID, "%d:0", &start)
+			start++
+		}
+		var unspents []string
+		var next string
+		for i := start; i < start+2 && i < total; i++ {
+			unspents = append(unspents, fmt.Sprintf(`{"id":"%d:0","value":%d}`, i, i+1))
+			if i+1 < total {
+				next = fmt.Sprintf("%d:0", i)
+			}
+		}
+		fmt.Fprintf(w, `{"unspents":[%s],"nextBatchPrevId":%q}`, strings.Join(unspents, ","), next)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	p := client.Coin("tbtc").Wallet.UnspentsPager("59cd72485007a239fb00282ed480da1f", nil)
+	var got []string
+	err := p.FetchConcurrently(context.Background(), bitgo.FetchOptions[bitgo.Unspent]{Workers: 4}, func(page *bitgo.Page[bitgo.Unspent]) error {
+		for _, u := range page.Items {
+			got = append(got, u.OutPoint())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"0:0", "1:0", "2:0", "3:0", "4:0", "5:0", "6:0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if requests != 4 {
+		t.Errorf("expected 4 pages fetched, got %d", requests)
+	}
+	if p.Next(context.Background()) {
+		t.Error("all pages must be fetched")
+	}
+}
+
+func TestCoinWalletUnspentsUnsupportedParams(t *testing.T) {
+	client := bitgo.NewClient(bitgo.WithBaseURL("http://0.0.0.0:1"))
+	for _, params := range []*bitgo.UnspentsParams{
+		{Target: 1000},
+		{Skip: 10},
+		{Instant: true},
+		{Limit: -1},
+	} {
+		p := client.Coin("tbtc").Wallet.UnspentsPager("59cd72485007a239fb00282ed480da1f", params)
+		if p.Next(context.Background()) || p.Err() == nil {
+			t.Errorf("expected validation error for %+v", params)
+		}
+	}
+}
+
+func TestCoinWalletConsolidate(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v2/tbtc/wallet/59cd72485007a239fb00282ed480da1f/consolidateUnspents" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"txid":"b8a828b98dbf32d9fd1875cbace9640ceb8c82626716b4a64203fdc79bb46d26","tx":"0100","status":"signed","transfer":{"fee":4840,"feeString":"4840"}}`))
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	wallet := client.Coin("tbtc").Wallet
+	got, err := wallet.Consolidate(context.Background(), "59cd72485007a239fb00282ed480da1f", &bitgo.WalletConsolidateParams{
+		WalletPassphrase: "root",
+		Limit:            50,
+		MinValue:         1000,
+		FeeRate:          20000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantBody := map[string]interface{}{
+		"walletPassphrase": "root",
+		"limit":            float64(50),
+		"minValue":         float64(1000),
+		"feeRate":          float64(20000),
+	}
+	if !reflect.DeepEqual(body, wantBody) {
+		t.Errorf("expected %v body, got %v", wantBody, body)
+	}
+	if got.TxID != "b8a828b98dbf32d9fd1875cbace9640ceb8c82626716b4a64203fdc79bb46d26" || got.Tx != "0100" || got.Status != "signed" || got.Fee != 4840 || len(got.Raw) == 0 {
+		t.Errorf("unexpected transaction %#v", got)
+	}
+
+	if _, err = wallet.Consolidate(context.Background(), "59cd72485007a239fb00282ed480da1f", &bitgo.WalletConsolidateParams{MaxIter: 2}); err == nil {
+		t.Error("expected max iterations to be rejected")
+	}
+}
+
+func TestCoin(t *testing.T) {
+	client := bitgo.NewClient()
+	if client.Coin("tbtc") != client.Coin("tbtc") || client.Coin("tbtc") == client.Coin("btc") {
+		t.Error("expected the same coin for the same name")
+	}
+	if _, err := client.NewCoinRequest(context.Background(), "", http.MethodGet, "wallet", nil, nil); err == nil {
+		t.Error("expected empty coin to be rejected")
+	}
+}
@@ -1,5 +1,7 @@
 package bitgo
 
+import "time"
+
 // The error types are based on HTTP status codes.
 const (
 	// ErrorTypeRequiresApproval indicates that request is accepted but requires approval.
@@ -8,11 +10,18 @@ const (
 	ErrorTypeInvalidRequest = "invalid_request_error"
 	// ErrorTypeAuthentication is returned when a request is not authenticated.
 	ErrorTypeAuthentication = "authentication_error"
+	// ErrorTypeUnlockRequired is returned when the session must be unlocked, e.g., to send coins.
+	ErrorTypeUnlockRequired = "unlock_required"
+	// ErrorTypeOTPRequired is returned when a request requires a one-time password.
+	ErrorTypeOTPRequired = "otp_required"
+	// ErrorTypeSignatureMismatch is returned when a response signature doesn't match, see HMACAuthenticator.
+	ErrorTypeSignatureMismatch = "signature_mismatch"
 	// ErrorTypeNotFound is returned when API resource is not found.
 	ErrorTypeNotFound = "not_found"
 	// ErrorTypeRateLimit indicates too many requests hit the API too quickly.
 	ErrorTypeRateLimit = "rate_limit_error"
-	// ErrorTypeAPI covers temporary problems with BitGo's API (50x status codes).
+	// ErrorTypeAPI covers other problems with BitGo's API, e.g., temporary errors (50x status codes)
+	// or unexpected status codes such as 409 Conflict.
 	ErrorTypeAPI = "api_error"
 )
 
@@ -25,6 +34,8 @@ type Error struct {
 	Body      string
 	Message   string `json:"error"`
 	RequestID string `json:"requestId"`
+	// RetryAfter is how long the server asked to wait before making a new request (429 and 50x status codes).
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e Error) Error() string {
@@ -42,8 +53,28 @@ func (e Error) IsInvalidRequest() bool {
 }
 
 // IsUnauthorized returns true if err caused by authentication problem.
+// The session which must be unlocked or requires a one-time password is unauthorized as well.
 func (e Error) IsUnauthorized() bool {
-	return e.Type == ErrorTypeAuthentication
+	switch e.Type {
+	case ErrorTypeAuthentication, ErrorTypeUnlockRequired, ErrorTypeOTPRequired:
+		return true
+	}
+	return false
+}
+
+// IsUnlockRequired returns true if err indicates that the session must be unlocked, see User.Unlock.
+func (e Error) IsUnlockRequired() bool {
+	return e.Type == ErrorTypeUnlockRequired
+}
+
+// IsOTPRequired returns true if err indicates that a one-time password is required.
+func (e Error) IsOTPRequired() bool {
+	return e.Type == ErrorTypeOTPRequired
+}
+
+// IsSignatureMismatch returns true if err indicates that the response isn't signed by BitGo, see HMACAuthenticator.
+func (e Error) IsSignatureMismatch() bool {
+	return e.Type == ErrorTypeSignatureMismatch
 }
 
 // IsNotFound returns true if err indicates that API resource is not found.
@@ -56,7 +87,9 @@ func (e Error) IsRateLimited() bool {
 	return e.Type == ErrorTypeRateLimit
 }
 
-// IsTemporary returns true if err is temporary API error (50x status codes).
+// IsTemporary returns true if err is API error of ErrorTypeAPI type.
+// Besides temporary errors (50x status codes) it covers unexpected status codes,
+// HTTPStatusCode tells them apart.
 func (e Error) IsTemporary() bool {
 	return e.Type == ErrorTypeAPI
 }
@@ -12,6 +12,9 @@ func TestIsUnauthorized(t *testing.T) {
 		want bool
 	}{
 		{bitgo.Error{Type: bitgo.ErrorTypeAuthentication}, true},
+		{bitgo.Error{Type: bitgo.ErrorTypeUnlockRequired, HTTPStatusCode: 401}, true},
+		{bitgo.Error{Type: bitgo.ErrorTypeOTPRequired, HTTPStatusCode: 401}, true},
+		{bitgo.Error{Type: bitgo.ErrorTypeSignatureMismatch}, false},
 		{bitgo.Error{Type: bitgo.ErrorTypeInvalidRequest}, false},
 		{bitgo.Error{Type: bitgo.ErrorTypeRateLimit}, false},
 		{bitgo.Error{Type: bitgo.ErrorTypeAPI}, false},
@@ -0,0 +1,177 @@
+package bitgo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FeeRate is a transaction fee rate in satoshis per kilobyte (sat/kB) as used by BitGo API.
+// Wallets often quote fee rates in satoshis per virtual byte (sat/vB) which is 1000 times smaller.
+type FeeRate int64
+
+// FeeRateFromSatPerVByte converts a fee rate in sat/vB to sat/kB rounding to the nearest integer.
+func FeeRateFromSatPerVByte(satPerVByte float64) FeeRate {
+	return FeeRate(math.Round(satPerVByte * 1000))
+}
+
+// ParseFeeRate parses a fee rate with an optional unit "sat/kB" (default) or "sat/vB",
+// e.g., "10000", "10000 sat/kB" or "12.5 sat/vB".
+func ParseFeeRate(s string) (FeeRate, error) {
+	num, unit := strings.TrimSpace(s), "sat/kb"
+	if i := strings.IndexFunc(num, unicode.IsLetter); i >= 0 {
+		num, unit = strings.TrimSpace(num[:i]), strings.ToLower(num[i:])
+	}
+
+	switch unit {
+	case "sat/kb":
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("bitgo: invalid fee rate %q", s)
+		}
+		return FeeRate(n), nil
+	case "sat/vb":
+		f, err := strconv.ParseFloat(num, 64)
+		if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+			return 0, fmt.Errorf("bitgo: invalid fee rate %q", s)
+		}
+		return FeeRateFromSatPerVByte(f), nil
+	}
+	return 0, fmt.Errorf("bitgo: unknown fee rate unit in %q", s)
+}
+
+// SatPerVByte returns the fee rate in sat/vB.
+func (r FeeRate) SatPerVByte() float64 {
+	return float64(r) / 1000
+}
+
+// String returns the fee rate in sat/kB, e.g., "10000 sat/kB".
+func (r FeeRate) String() string {
+	return strconv.FormatInt(int64(r), 10) + " sat/kB"
+}
+
+// Set parses the fee rate so it can be used as a command line flag, see flag.Value.
+func (r *FeeRate) Set(s string) error {
+	v, err := ParseFeeRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// Fee returns the fee in satoshis of a transaction of the virtual size in vbytes.
+// The fee is rounded up, so the transaction pays at least the fee rate.
+func (r FeeRate) Fee(vsize int) Amount {
+	return Amount((int64(r)*int64(vsize) + 999) / 1000)
+}
+
+// EstimateFee returns the fee in satoshis of a wallet transaction which spends the number of inputs
+// of the chain's script type and has the number of outputs, see EstimateVSize.
+func (r FeeRate) EstimateFee(inputs int, chain Chain, outputs int) Amount {
+	return r.Fee(EstimateVSize(inputs, chain, outputs))
+}
+
+// Virtual sizes in vbytes of transaction parts used to estimate fees.
+const (
+	// txOverheadVSize is the size of version, locktime, input and output counts and SegWit marker.
+	txOverheadVSize = 11
+	// txOutputVSize is the size of a typical output.
+	txOutputVSize = 34
+	// P2SH, P2SH-P2WSH and P2WSH inputs spending 2-of-3 multisig wallet outputs.
+	p2shInputVSize      = 297
+	p2shP2WSHInputVSize = 139
+	p2wshInputVSize     = 105
+)
+
+// EstimateVSize returns the estimated virtual size in vbytes of a wallet transaction which spends
+// the number of 2-of-3 multisig inputs of the chain's script type and has the number of outputs.
+func EstimateVSize(inputs int, chain Chain, outputs int) int {
+	inputVSize := p2shInputVSize
+	switch chain {
+	case ChainP2SHP2WSH, ChainP2SHP2WSHChange:
+		inputVSize = p2shP2WSHInputVSize
+	case ChainP2WSH, ChainP2WSHChange:
+		inputVSize = p2wshInputVSize
+	}
+	return txOverheadVSize + inputs*inputVSize + outputs*txOutputVSize
+}
+
+// FeeService communicates with the fee estimation API endpoint.
+// See bitgomock package for its mock implementation.
+type FeeService interface {
+	// Estimate returns the fee rate needed to confirm a transaction within the number of blocks.
+	Estimate(ctx context.Context, numBlocks int) (*FeeEstimate, error)
+}
+
+// feeService communicates with the fee estimation API endpoint.
+type feeService struct {
+	client *Client
+}
+
+var _ FeeService = (*feeService)(nil)
+
+// FeeEstimate is a response we get from tx/fee API endpoint.
+// For more details, see https://bitgo.github.io/bitgo-docs/#estimate-transaction-fees.
+type FeeEstimate struct {
+	// The recommended fee rate to confirm a transaction within NumBlocks blocks.
+	FeePerKB FeeRate `json:"feePerKb"`
+	// The recommended fee rate of a child transaction which pays for its unconfirmed parent.
+	CPFPFeePerKB FeeRate `json:"cpfpFeePerKb"`
+	// The number of blocks the estimate targets.
+	NumBlocks int `json:"numBlocks"`
+	// The confidence of the estimate in percents.
+	Confidence int `json:"confidence"`
+	// The multiplier applied by BitGo to the fee rate.
+	Multiplier float64 `json:"multiplier"`
+	// Fee rates by the number of blocks to confirm within.
+	FeeByBlockTarget map[int]FeeRate `json:"feeByBlockTarget"`
+}
+
+// ForBlocks returns the fee rate to confirm within the number of blocks.
+// It picks the bucket with the largest block target which doesn't exceed numBlocks,
+// and falls back to FeePerKB if there is no such bucket.
+func (e *FeeEstimate) ForBlocks(numBlocks int) FeeRate {
+	targets := make([]int, 0, len(e.FeeByBlockTarget))
+	for n := range e.FeeByBlockTarget {
+		targets = append(targets, n)
+	}
+	sort.Ints(targets)
+
+	rate := e.FeePerKB
+	for _, n := range targets {
+		if n > numBlocks {
+			break
+		}
+		rate = e.FeeByBlockTarget[n]
+	}
+	return rate
+}
+
+// Estimate returns the fee rate needed to confirm a transaction within the number of blocks.
+// Zero numBlocks means the API default is used (2 blocks).
+func (s *feeService) Estimate(ctx context.Context, numBlocks int) (*FeeEstimate, error) {
+	if numBlocks < 0 {
+		return nil, fmt.Errorf("bitgo: fee estimate number of blocks must not be negative, got %d", numBlocks)
+	}
+	var params url.Values
+	if numBlocks > 0 {
+		params = url.Values{"numBlocks": {strconv.Itoa(numBlocks)}}
+	}
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "tx/fee", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var e FeeEstimate
+	if _, err = s.client.Do(req, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
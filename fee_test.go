@@ -0,0 +1,169 @@
+package bitgo_test
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestFeeEstimate(t *testing.T) {
+	filename := filepath.Join("testdata", "fee.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	want := bitgo.FeeEstimate{
+		FeePerKB:     15000,
+		CPFPFeePerKB: 15000,
+		NumBlocks:    2,
+		Confidence:   80,
+		Multiplier:   1,
+		FeeByBlockTarget: map[int]bitgo.FeeRate{
+			1:  20000,
+			2:  15000,
+			6:  10000,
+			12: 5000,
+		},
+	}
+
+	got, err := client.Fee.Estimate(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("should be %#v, not %#v", want, *got)
+	}
+
+	if _, err = client.Fee.Estimate(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	wantRequests := []string{
+		"GET /api/v1/tx/fee?numBlocks=2",
+		"GET /api/v1/tx/fee",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected %v requests, got %v", wantRequests, requests)
+	}
+
+	if _, err = client.Fee.Estimate(context.Background(), -1); err == nil {
+		t.Errorf("negative number of blocks must not be accepted")
+	}
+}
+
+func TestFeeEstimateForBlocks(t *testing.T) {
+	e := bitgo.FeeEstimate{
+		FeePerKB: 15000,
+		FeeByBlockTarget: map[int]bitgo.FeeRate{
+			2:  15000,
+			6:  10000,
+			12: 5000,
+		},
+	}
+	tests := map[int]bitgo.FeeRate{
+		1:   15000,
+		2:   15000,
+		5:   15000,
+		6:   10000,
+		11:  10000,
+		144: 5000,
+	}
+	for numBlocks, want := range tests {
+		if got := e.ForBlocks(numBlocks); got != want {
+			t.Errorf("ForBlocks(%d) = %d, want %d", numBlocks, got, want)
+		}
+	}
+}
+
+func TestParseFeeRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bitgo.FeeRate
+	}{
+		{"10000", 10000},
+		{"10000 sat/kB", 10000},
+		{"10000sat/kb", 10000},
+		{"12.5 sat/vB", 12500},
+		{"1 sat/vb", 1000},
+		{"0.0004 sat/vB", 0},
+		{"0", 0},
+	}
+	for _, test := range tests {
+		got, err := bitgo.ParseFeeRate(test.in)
+		if err != nil {
+			t.Errorf("ParseFeeRate(%q) failed: %v", test.in, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseFeeRate(%q) = %d, want %d", test.in, got, test.want)
+		}
+	}
+
+	for _, in := range []string{"", "-1", "12.5", "12.5 sat/kB", "10 BTC/kB", "NaN sat/vB", "sat/vB"} {
+		if _, err := bitgo.ParseFeeRate(in); err == nil {
+			t.Errorf("ParseFeeRate(%q) must fail", in)
+		}
+	}
+}
+
+func TestFeeRateFlag(t *testing.T) {
+	var r bitgo.FeeRate
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&r, "fee-rate", "")
+	if err := fs.Parse([]string{"-fee-rate", "20 sat/vB"}); err != nil {
+		t.Fatal(err)
+	}
+	if r != 20000 || r.SatPerVByte() != 20 || r.String() != "20000 sat/kB" {
+		t.Errorf("unexpected fee rate %s", r)
+	}
+	if got := bitgo.FeeRateFromSatPerVByte(1.2345); got != 1235 {
+		t.Errorf("expected 1235 sat/kB, got %d", got)
+	}
+}
+
+func TestFeeRateEstimateFee(t *testing.T) {
+	tests := []struct {
+		inputs  int
+		chain   bitgo.Chain
+		outputs int
+		vsize   int
+	}{
+		{1, bitgo.ChainP2SH, 2, 11 + 297 + 2*34},
+		{3, bitgo.ChainP2SHChange, 1, 11 + 3*297 + 34},
+		{2, bitgo.ChainP2SHP2WSH, 2, 11 + 2*139 + 2*34},
+		{10, bitgo.ChainP2WSHChange, 1, 11 + 10*105 + 34},
+	}
+	for _, test := range tests {
+		if got := bitgo.EstimateVSize(test.inputs, test.chain, test.outputs); got != test.vsize {
+			t.Errorf("EstimateVSize(%d, %d, %d) = %d, want %d", test.inputs, test.chain, test.outputs, got, test.vsize)
+		}
+	}
+
+	r := bitgo.FeeRate(10000)
+	if got := r.EstimateFee(2, bitgo.ChainP2WSH, 2); got != 2890 {
+		t.Errorf("expected 2890 sat fee, got %d", got)
+	}
+	// The fee is rounded up to pay at least the fee rate.
+	if got := bitgo.FeeRate(1001).Fee(1000); got != 1001 {
+		t.Errorf("expected 1001 sat fee, got %d", got)
+	}
+	if got := bitgo.FeeRate(1001).Fee(999); got != 1000 {
+		t.Errorf("expected 1000 sat fee, got %d", got)
+	}
+}
@@ -0,0 +1,169 @@
+package bitgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Logger is a structured logger used by Client to log API requests.
+// It's compatible with *slog.Logger, for example,
+// slog.New(slog.NewTextHandler(os.Stderr, nil)).
+type Logger interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+	Log(ctx context.Context, level slog.Level, msg string, args ...interface{})
+}
+
+// redacted replaces secrets in logged request and response bodies.
+const redacted = "[REDACTED]"
+
+// secretKeys are substrings of JSON keys whose values are never logged.
+var secretKeys = []string{"passphrase", "password", "prv", "token", "secret", "otp", "authorization"}
+
+// secretValues matches extended private keys found anywhere in a body.
+var secretValues = regexp.MustCompile(`\b[xt]prv[1-9A-HJ-NP-Za-km-z]{100,}`)
+
+// logRequests returns a middleware which logs a summary of every request:
+// method, path, status, duration and BitGo request ID.
+// Successful requests are logged at info level, API errors at warning level
+// and network errors at error level.
+func logRequests(l Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req, v)
+
+			var status int
+			var requestID string
+			if resp != nil {
+				status = resp.StatusCode
+				requestID = resp.Header.Get("Request-Id")
+			}
+			level := slog.LevelInfo
+			msg := "bitgo: request completed"
+			if e, ok := err.(Error); ok {
+				level = slog.LevelWarn
+				msg = "bitgo: request failed"
+				status = e.HTTPStatusCode
+				if e.RequestID != "" {
+					requestID = e.RequestID
+				}
+			} else if err != nil {
+				level = slog.LevelError
+				msg = "bitgo: request failed"
+			}
+
+			args := []interface{}{
+				"method", req.Method,
+				"path", req.URL.Path,
+				"status", status,
+				"duration", time.Since(start),
+			}
+			if requestID != "" {
+				args = append(args, "requestId", requestID)
+			}
+			if err != nil {
+				args = append(args, "error", err.Error())
+			}
+			l.Log(req.Context(), level, msg, args...)
+			return resp, err
+		})
+	}
+}
+
+// logBodies logs request and response bodies at debug level with secrets redacted.
+// The response body is read in full and replaced with an in-memory copy.
+func logBodies(l Logger, req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	if !l.Enabled(ctx, slog.LevelDebug) {
+		return send(req)
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		if r, err := req.GetBody(); err == nil {
+			body, _ = ioutil.ReadAll(r)
+			r.Close()
+		}
+	}
+	l.Log(ctx, slog.LevelDebug, "bitgo: sending request",
+		"method", req.Method,
+		"path", req.URL.Path,
+		"body", Redact(body),
+	)
+
+	resp, err := send(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	l.Log(ctx, slog.LevelDebug, "bitgo: received response",
+		"method", req.Method,
+		"path", req.URL.Path,
+		"status", resp.StatusCode,
+		"body", Redact(body),
+	)
+	return resp, nil
+}
+
+// Redact replaces values of secret JSON keys such as walletPassphrase, xprv or otp
+// and extended private keys in the body. It's used to log bodies and record cassettes.
+// Bodies which are not JSON objects have only private keys redacted.
+func Redact(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return secretValues.ReplaceAllString(string(body), redacted)
+	}
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if isSecretKey(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(val)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	case string:
+		return secretValues.ReplaceAllString(v, redacted)
+	}
+	return v
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
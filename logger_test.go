@@ -0,0 +1,81 @@
+package bitgo_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	const xprv = "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"hash":"abc","status":"accepted","note":"` + xprv + `"}]`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("swordfish"),
+		bitgo.WithLogger(logger),
+	)
+	_, err := client.Wallet.Consolidate(context.Background(), "abc", &bitgo.WalletConsolidateParams{
+		WalletPassphrase: "hunter2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"hunter2", "swordfish", xprv} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log must not contain %q:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{
+		"method=PUT",
+		"path=/api/v1/wallet/abc/consolidateunspents",
+		"status=200",
+		"duration=",
+		"[REDACTED]",
+		`\"hash\":\"abc\"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log must contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLoggerLevels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"unauthorized","requestId":"bj9h0dap1723kadrsnfkvsinz"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithLogger(logger),
+	)
+	if err := client.Wallet.Unspents(context.Background(), "abc", nil, nil); err == nil {
+		t.Fatal("expected unauthorized error")
+	}
+
+	out := buf.String()
+	for _, want := range []string{"level=WARN", "status=401", "requestId=bj9h0dap1723kadrsnfkvsinz", "error=unauthorized"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log must contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "level=DEBUG") {
+		t.Errorf("bodies must not be logged at info level:\n%s", out)
+	}
+}
@@ -0,0 +1,161 @@
+package bitgo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Doer sends an HTTP request and unmarshals the response into v.
+type Doer interface {
+	Do(req *http.Request, v interface{}) (*http.Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer.
+type DoerFunc func(req *http.Request, v interface{}) (*http.Response, error)
+
+// Do calls f(req, v).
+func (f DoerFunc) Do(req *http.Request, v interface{}) (*http.Response, error) {
+	return f(req, v)
+}
+
+// Middleware wraps the next Doer in a chain to inspect or modify requests and responses,
+// for example, to inject headers, sign requests, collect metrics or return cached responses.
+type Middleware func(next Doer) Doer
+
+// DefaultMiddleware returns the built-in middleware chain in the order requests pass through it:
+// JSON decoding, error classification and bearer authentication.
+// Use it with WithMiddlewareStack to replace or reorder the built-in behaviours.
+func DefaultMiddleware(token string) []Middleware {
+	return defaultMiddleware(BearerAuthenticator{}, token)
+}
+
+// defaultMiddleware returns the built-in middleware chain which authenticates requests with the authenticator.
+func defaultMiddleware(a Authenticator, token string) []Middleware {
+	return []Middleware{
+		DecodeJSON(),
+		ClassifyErrors(),
+		Authenticate(a, token),
+	}
+}
+
+// BearerAuth returns a middleware which authenticates requests with the access token
+// unless they are already authenticated, e.g., with a session token.
+func BearerAuth(token string) Middleware {
+	return Authenticate(BearerAuthenticator{}, token)
+}
+
+// Authenticate returns a middleware which authenticates requests with the authenticator and verifies responses.
+// A request is authenticated with the bearer token it already has, e.g., the session token set by NewRequest,
+// otherwise with the access token. Requests without a token or with a non-bearer Authorization header pass through.
+// It must be placed after ClassifyErrors in a chain so responses are verified before they are classified and decoded.
+func Authenticate(a Authenticator, token string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+			t := token
+			if auth := req.Header.Get("Authorization"); auth != "" {
+				var ok bool
+				if t, ok = strings.CutPrefix(auth, "Bearer "); !ok {
+					return next.Do(req, v)
+				}
+			}
+			if t == "" {
+				return next.Do(req, v)
+			}
+
+			// The request is cloned, so a retry is authenticated with the original token rather than the credentials.
+			req = req.Clone(req.Context())
+			if err := a.Authenticate(req, t); err != nil {
+				return nil, err
+			}
+			resp, err := next.Do(req, v)
+			if err != nil {
+				return resp, err
+			}
+			return resp, a.Verify(req, resp, t)
+		})
+	}
+}
+
+// DecodeJSON returns a middleware which decodes a successful response into v.
+// The whole response body is buffered in a pooled buffer before it's decoded.
+// It must be placed before ClassifyErrors in a chain so only successful responses reach it.
+func DecodeJSON() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+			resp, err := next.Do(req, v)
+			if err != nil || v == nil {
+				return resp, err
+			}
+			defer resp.Body.Close()
+
+			return resp, decodeBody(resp.Body, v)
+		})
+	}
+}
+
+// ClassifyErrors returns a middleware which turns unsuccessful responses into Error
+// based on HTTP status code. Authentication errors which need an unlock or OTP
+// are told apart by needsUnlock and needsOTP fields of the response body.
+// The raw response body is preserved in Error.Body, a body larger than 64 KB
+// or WithMaxResponseSize limit is truncated.
+func ClassifyErrors() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+			resp, err := next.Do(req, v)
+			if err != nil || resp.StatusCode == http.StatusOK {
+				return resp, err
+			}
+			defer resp.Body.Close()
+
+			body, err := readErrorBody(resp.Body)
+			if err != nil {
+				return resp, err
+			}
+			e := Error{
+				HTTPStatusCode: resp.StatusCode,
+				Body:           string(body),
+			}
+			_ = json.Unmarshal(body, &e)
+
+			switch resp.StatusCode {
+			case http.StatusAccepted:
+				e.Type = ErrorTypeRequiresApproval
+			case http.StatusBadRequest:
+				e.Type = ErrorTypeInvalidRequest
+			case http.StatusUnauthorized, http.StatusForbidden:
+				var needs struct {
+					Unlock bool `json:"needsUnlock"`
+					OTP    bool `json:"needsOTP"`
+				}
+				_ = json.Unmarshal(body, &needs)
+				switch {
+				case needs.Unlock:
+					e.Type = ErrorTypeUnlockRequired
+				case needs.OTP:
+					e.Type = ErrorTypeOTPRequired
+				default:
+					e.Type = ErrorTypeAuthentication
+				}
+			case http.StatusNotFound:
+				e.Type = ErrorTypeNotFound
+			case http.StatusTooManyRequests:
+				e.Type = ErrorTypeRateLimit
+				e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			default:
+				e.Type = ErrorTypeAPI
+				e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			}
+			return resp, e
+		})
+	}
+}
+
+// chain wraps the doer with middleware so the first one is the outermost.
+func chain(d Doer, mw ...Middleware) Doer {
+	for i := len(mw) - 1; i >= 0; i-- {
+		d = mw[i](d)
+	}
+	return d
+}
@@ -0,0 +1,101 @@
+package bitgo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Trace"); got != "outer,inner" {
+			t.Errorf("expected X-Trace header set by middleware, got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer swordfish" {
+			t.Errorf("expected bearer auth, got %q", got)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	trace := func(name string) bitgo.Middleware {
+		return func(next bitgo.Doer) bitgo.Doer {
+			return bitgo.DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+				h := name
+				if prev := req.Header.Get("X-Trace"); prev != "" {
+					h = prev + "," + name
+				}
+				req.Header.Set("X-Trace", h)
+				return next.Do(req, v)
+			})
+		}
+	}
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("swordfish"),
+		bitgo.WithMiddleware(trace("outer"), trace("inner")),
+	)
+	if _, err := client.Wallet.Consolidate(context.Background(), "", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMiddlewareFaultInjection(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	want := bitgo.Error{Type: bitgo.ErrorTypeRateLimit, HTTPStatusCode: http.StatusTooManyRequests}
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithMiddleware(func(next bitgo.Doer) bitgo.Doer {
+			return bitgo.DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+				return nil, want
+			})
+		}),
+	)
+	_, err := client.Wallet.Consolidate(context.Background(), "", nil)
+	if err != want {
+		t.Fatalf("should be %#v, not %#v", want, err)
+	}
+	if requests != 0 {
+		t.Errorf("request must not reach the server, got %d requests", requests)
+	}
+}
+
+func TestMiddlewareStack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("expected no bearer auth, got %q", got)
+		}
+		if got := r.Header.Get("X-Api-Key"); got != "swordfish" {
+			t.Errorf("expected custom auth, got %q", got)
+		}
+		w.Write([]byte(`[{"hash":"abc"}]`))
+	}))
+	defer srv.Close()
+
+	apiKey := func(next bitgo.Doer) bitgo.Doer {
+		return bitgo.DoerFunc(func(req *http.Request, v interface{}) (*http.Response, error) {
+			req.Header.Set("X-Api-Key", "swordfish")
+			return next.Do(req, v)
+		})
+	}
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("swordfish"),
+		bitgo.WithMiddlewareStack(apiKey, bitgo.DecodeJSON(), bitgo.ClassifyErrors()),
+	)
+	tt, err := client.Wallet.Consolidate(context.Background(), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tt) != 1 || tt[0].TxID != "abc" {
+		t.Errorf("unexpected transactions %#v", tt)
+	}
+}
@@ -0,0 +1,316 @@
+package bitgo
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Page is a page of results retrieved from a list endpoint.
+type Page[T any] struct {
+	ListMeta
+	Items []T
+	// OutOfOrder is set when the page is delivered before some of the preceding pages,
+	// see FetchOptions.Unordered.
+	OutOfOrder bool
+}
+
+// PageFunc fetches a page of results starting from skip index.
+type PageFunc[T any] func(ctx context.Context, skip int) (*Page[T], error)
+
+// Pager paginates through a list endpoint using skip and total from ListMeta.
+// Pages are fetched one at a time when Next is called, so pagination can be stopped at any time.
+//
+//	p := c.Wallet.UnspentsPager(walletID, nil)
+//	for p.Next(ctx) {
+//		for _, utxo := range p.Page().Items {
+//			fmt.Println(utxo.Value)
+//		}
+//	}
+//	if err := p.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type Pager[T any] struct {
+	fetch PageFunc[T]
+	skip  int
+	page  *Page[T]
+	err   error
+	done  bool
+	// cursor is set when a page can only be fetched after the preceding one,
+	// so pages can't be fetched concurrently by their skip index.
+	cursor bool
+}
+
+// NewPager returns a Pager which fetches pages using f starting from skip index.
+// The skip index lets you resume pagination from an offset returned by Pager.Offset.
+// Pages can be fetched concurrently, see Pager.FetchConcurrently, so f must not modify
+// variables shared between its calls, e.g., query params captured by the closure.
+func NewPager[T any](skip int, f PageFunc[T]) *Pager[T] {
+	return &Pager[T]{
+		fetch: f,
+		skip:  skip,
+	}
+}
+
+// newCursorPager returns a Pager which fetches pages using f one after another,
+// e.g., when the next page is requested with the ID of the last item.
+func newCursorPager[T any](f PageFunc[T]) *Pager[T] {
+	return &Pager[T]{
+		fetch:  f,
+		cursor: true,
+	}
+}
+
+// Next fetches the next page which is then available through Page.
+// It returns false when there are no more pages or an error occurred, see Err.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	page, err := p.fetch(ctx, p.skip)
+	if err != nil {
+		p.err = err
+		p.page = nil
+		return false
+	}
+	p.page = page
+
+	count := page.Count
+	if count == 0 {
+		count = len(page.Items)
+	}
+	p.skip += count
+	p.done = count == 0 || p.skip >= page.Total
+	return true
+}
+
+// Page returns the page fetched by the most recent call to Next.
+func (p *Pager[T]) Page() *Page[T] {
+	return p.page
+}
+
+// Err returns the first error that occurred while fetching pages.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Offset returns the skip index of the next page.
+// It can be passed to NewPager to resume pagination later.
+func (p *Pager[T]) Offset() int {
+	return p.skip
+}
+
+// Pages returns an iterator over the remaining pages.
+// An error stops the iteration and is yielded with a nil page.
+func (p *Pager[T]) Pages(ctx context.Context) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		for p.Next(ctx) {
+			if !yield(p.page, nil) {
+				return
+			}
+		}
+		if p.err != nil {
+			yield(nil, p.err)
+		}
+	}
+}
+
+// All returns an iterator over items of the remaining pages.
+// An error stops the iteration and is yielded with a zero item.
+//
+//	for utxo, err := range c.Wallet.UnspentsPager(walletID, nil).All(ctx) {
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Println(utxo.Value)
+//	}
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range p.Pages(ctx) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// FetchOptions configures concurrent fetching of pages, see Pager.FetchConcurrently.
+type FetchOptions[T any] struct {
+	// Workers is a max number of pages fetched at the same time (defaults to 1).
+	Workers int
+	// Unordered delivers pages as soon as they're fetched instead of in order of their skip index.
+	// Pages which overtook a preceding page have OutOfOrder flag set.
+	Unordered bool
+	// Key identifies an item, e.g., Unspent.OutPoint. If set, items seen on preceding pages are removed
+	// because they can shift between pages while the list is changing.
+	Key func(T) string
+}
+
+// FetchConcurrently fetches the first page to learn the total number of items,
+// then fetches the remaining pages with a bounded number of workers and invokes f for each page.
+// All workers stop on the first error either returned by f or occurred while fetching,
+// or when ctx is cancelled. The error is returned and available through Err.
+// If the list grew while it was fetched, the rest of it can be fetched with Next.
+// Pages of cursor-paginated lists, e.g., CoinWalletService.UnspentsPager, are fetched one after another until done.
+func (p *Pager[T]) FetchConcurrently(ctx context.Context, opts FetchOptions[T], f func(*Page[T]) error) error {
+	var seen map[string]bool
+	if opts.Key != nil {
+		seen = make(map[string]bool)
+	}
+	deliver := func(page *Page[T]) error {
+		if seen != nil {
+			items := page.Items[:0:0]
+			for _, item := range page.Items {
+				k := opts.Key(item)
+				if !seen[k] {
+					seen[k] = true
+					items = append(items, item)
+				}
+			}
+			page.Items = items
+		}
+		return f(page)
+	}
+
+	if !p.Next(ctx) {
+		return p.err
+	}
+	if err := deliver(p.page); err != nil {
+		p.err = err
+		return err
+	}
+	if p.done {
+		return nil
+	}
+	if p.cursor {
+		for p.Next(ctx) {
+			if err := deliver(p.page); err != nil {
+				p.err = err
+				return err
+			}
+		}
+		return p.err
+	}
+
+	// The remaining pages are fetched by their skip index using the size of the first page.
+	step := len(p.page.Items)
+	if p.page.Count > 0 {
+		step = p.page.Count
+	}
+	total := p.page.Total
+	var offsets []int
+	for skip := p.skip; skip < total; skip += step {
+		offsets = append(offsets, skip)
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		i    int
+		page *Page[T]
+		err  error
+	}
+	jobs := make(chan int)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if fetchCtx.Err() != nil {
+					return
+				}
+				page, err := p.fetch(fetchCtx, offsets[i])
+				select {
+				case results <- result{i: i, page: page, err: err}:
+				case <-fetchCtx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range offsets {
+			select {
+			case jobs <- i:
+			case <-fetchCtx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var err error
+	// next is an index of the earliest page which wasn't delivered yet.
+	next := 0
+	pending := make(map[int]*Page[T])
+	delivered := make(map[int]bool)
+	for r := range results {
+		if r.err != nil {
+			err = r.err
+			break
+		}
+		if r.page.Total > total {
+			total = r.page.Total
+		}
+
+		if opts.Unordered {
+			r.page.OutOfOrder = r.i != next
+			if err = deliver(r.page); err != nil {
+				break
+			}
+			delivered[r.i] = true
+			for delivered[next] {
+				delete(delivered, next)
+				next++
+			}
+			continue
+		}
+
+		pending[r.i] = r.page
+		for pending[next] != nil && err == nil {
+			err = deliver(pending[next])
+			delete(pending, next)
+			next++
+		}
+		if err != nil {
+			break
+		}
+	}
+	// Workers are stopped and their results are discarded.
+	cancel()
+	for range results {
+	}
+
+	if err == nil && next < len(offsets) {
+		// Workers stopped early because ctx was cancelled.
+		if err = ctx.Err(); err == nil {
+			err = context.Canceled
+		}
+	}
+	if err != nil {
+		p.err = err
+		return err
+	}
+	p.skip = offsets[len(offsets)-1] + step
+	p.done = p.skip >= total
+	return nil
+}
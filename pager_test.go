@@ -0,0 +1,219 @@
+package bitgo_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+// numbers returns a PageFunc which lists total numbers limit per page and records skip indexes.
+func numbers(total, limit int, skips *[]int) bitgo.PageFunc[int] {
+	return func(ctx context.Context, skip int) (*bitgo.Page[int], error) {
+		*skips = append(*skips, skip)
+		page := bitgo.Page[int]{ListMeta: bitgo.ListMeta{Start: skip, Total: total}}
+		for i := skip; i < total && i < skip+limit; i++ {
+			page.Items = append(page.Items, i)
+		}
+		page.Count = len(page.Items)
+		return &page, nil
+	}
+}
+
+func TestPagerNext(t *testing.T) {
+	var skips []int
+	p := bitgo.NewPager(0, numbers(5, 2, &skips))
+
+	var got []int
+	for p.Next(context.Background()) {
+		page := p.Page()
+		if page.Total != 5 {
+			t.Errorf("expected total 5, got %d", page.Total)
+		}
+		got = append(got, page.Items...)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if want := []int{0, 2, 4}; !reflect.DeepEqual(skips, want) {
+		t.Errorf("expected skips %v, got %v", want, skips)
+	}
+	if p.Offset() != 5 {
+		t.Errorf("expected offset 5, got %d", p.Offset())
+	}
+}
+
+func TestPagerResume(t *testing.T) {
+	var skips []int
+	p := bitgo.NewPager(0, numbers(5, 2, &skips))
+	var got []int
+	for n, err := range p.All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n)
+		if n == 2 {
+			break
+		}
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if want := []int{0, 2}; !reflect.DeepEqual(skips, want) {
+		t.Errorf("iteration must stop early, fetched pages %v", skips)
+	}
+
+	// Pagination resumes from the page after the last fetched one.
+	p = bitgo.NewPager(p.Offset(), numbers(5, 2, &skips))
+	got = got[:0]
+	for n, err := range p.All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n)
+	}
+	if want := []int{4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	want := errors.New("boom")
+	p := bitgo.NewPager(0, func(ctx context.Context, skip int) (*bitgo.Page[int], error) {
+		if skip > 0 {
+			return nil, want
+		}
+		return &bitgo.Page[int]{ListMeta: bitgo.ListMeta{Count: 1, Total: 2}, Items: []int{0}}, nil
+	})
+
+	var got []int
+	var err error
+	for n, e := range p.All(context.Background()) {
+		if e != nil {
+			err = e
+			continue
+		}
+		got = append(got, n)
+	}
+	if err != want {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+	if !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("expected items of the first page, got %v", got)
+	}
+	if p.Next(context.Background()) {
+		t.Error("pager must stop after an error")
+	}
+}
+
+// shiftingNumbers returns a safe for concurrent use PageFunc which lists total numbers limit per page.
+// Later pages are fetched faster, and items shift by one starting from the third page.
+func shiftingNumbers(total, limit int) bitgo.PageFunc[int] {
+	return func(ctx context.Context, skip int) (*bitgo.Page[int], error) {
+		time.Sleep(time.Duration(total-skip) * time.Millisecond)
+		page := bitgo.Page[int]{ListMeta: bitgo.ListMeta{Start: skip, Total: total}}
+		start := skip
+		if skip >= 2*limit {
+			start--
+		}
+		for i := start; i < total && i < start+limit; i++ {
+			page.Items = append(page.Items, i)
+		}
+		page.Count = len(page.Items)
+		return &page, ctx.Err()
+	}
+}
+
+func TestFetchConcurrently(t *testing.T) {
+	p := bitgo.NewPager(0, shiftingNumbers(10, 2))
+	opts := bitgo.FetchOptions[int]{
+		Workers: 3,
+		Key:     strconv.Itoa,
+	}
+	var got []int
+	err := p.FetchConcurrently(context.Background(), opts, func(page *bitgo.Page[int]) error {
+		if page.OutOfOrder {
+			t.Errorf("page %d must be delivered in order", page.Start)
+		}
+		got = append(got, page.Items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The item 3 is seen twice, because it shifted from the second page to the third one.
+	if want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if p.Next(context.Background()) {
+		t.Error("all pages must be fetched")
+	}
+}
+
+func TestFetchConcurrentlyUnordered(t *testing.T) {
+	p := bitgo.NewPager(0, shiftingNumbers(10, 2))
+	opts := bitgo.FetchOptions[int]{
+		Workers:   4,
+		Unordered: true,
+	}
+	var got []int
+	var outOfOrder int
+	err := p.FetchConcurrently(context.Background(), opts, func(page *bitgo.Page[int]) error {
+		if page.OutOfOrder {
+			outOfOrder++
+		}
+		got = append(got, page.Items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 10 {
+		t.Errorf("expected 10 items with a duplicate, got %v", got)
+	}
+	if outOfOrder == 0 {
+		t.Error("later pages are fetched faster and must be flagged as out of order")
+	}
+}
+
+func TestFetchConcurrentlyError(t *testing.T) {
+	want := errors.New("boom")
+	var mu sync.Mutex
+	var fetched int
+	p := bitgo.NewPager(0, func(ctx context.Context, skip int) (*bitgo.Page[int], error) {
+		mu.Lock()
+		fetched++
+		mu.Unlock()
+		if skip == 2 {
+			return nil, want
+		}
+		if skip > 2 {
+			// Other workers are stopped when an error occurs.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &bitgo.Page[int]{ListMeta: bitgo.ListMeta{Count: 2, Total: 100}, Items: []int{0, 1}}, nil
+	})
+
+	err := p.FetchConcurrently(context.Background(), bitgo.FetchOptions[int]{Workers: 3}, func(*bitgo.Page[int]) error {
+		return nil
+	})
+	if err != want {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+	if p.Err() != want {
+		t.Errorf("expected pager error %v, got %v", want, p.Err())
+	}
+	// Each worker may pick up one more page before it notices the error.
+	if fetched > 1+2*3 {
+		t.Errorf("expected at most 7 of 50 pages fetched, got %d", fetched)
+	}
+}
@@ -0,0 +1,222 @@
+package bitgo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles outgoing requests using a token bucket algorithm
+// to stay under BitGo's API quota.
+// The same RateLimiter can be shared by many Clients which use the same access token,
+// and it is safe for concurrent use by multiple goroutines.
+type RateLimiter struct {
+	mu        sync.Mutex
+	bucket    tokenBucket
+	endpoints []*endpointLimit
+	stats     RateLimiterStats
+}
+
+// RateLimiterStats describes how much requests were throttled by RateLimiter.
+type RateLimiterStats struct {
+	// Requests is a number of requests passed through the limiter.
+	Requests int64
+	// Delayed is a number of requests which had to wait for their turn.
+	Delayed int64
+	// Waiting is a number of requests which are currently waiting.
+	Waiting int
+	// TotalWait is a total time requests spent waiting.
+	TotalWait time.Duration
+	// MaxWait is the longest time a request had to wait.
+	MaxWait time.Duration
+}
+
+// NewRateLimiter returns a RateLimiter which allows r requests per second
+// with bursts of at most burst requests.
+// Zero rate means requests are not limited unless they match endpoint limits, see LimitEndpoint.
+func NewRateLimiter(r float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		bucket: newTokenBucket(r, burst),
+	}
+}
+
+// LimitEndpoint sets a separate budget of r requests per second with bursts of burst requests
+// for API endpoints matching the path pattern. A request must fit both the endpoint and the overall budget.
+// The pattern is relative to the base URL and /api/v1/, e.g., "wallet/:id/unspents", API v2 patterns start with "api/v2",
+// e.g., "api/v2/:coin/wallet/:id/unspents",
+// where a segment starting with colon matches any segment and "*" matches the rest of the path.
+// If methods are given, only requests with those HTTP methods share the budget,
+// e.g., LimitEndpoint("*", 1, 1, "POST", "PUT") limits all mutating calls.
+// Endpoint limits are matched in the order they were added.
+func (l *RateLimiter) LimitEndpoint(pattern string, r float64, burst int, methods ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.endpoints = append(l.endpoints, &endpointLimit{
+		pattern: strings.Split(strings.Trim(pattern, "/"), "/"),
+		methods: methods,
+		bucket:  newTokenBucket(r, burst),
+	})
+}
+
+// Stats returns current wait statistics.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Wait blocks until the request is allowed to be sent or ctx is cancelled.
+// The request path is matched against endpoint limits starting from its API version, e.g., "/api/v1/",
+// so base URLs with a path prefix are supported.
+func (l *RateLimiter) Wait(ctx context.Context, req *http.Request) error {
+	path := req.URL.Path
+	if i := strings.Index(path, "/api/v"); i > 0 {
+		path = path[i:]
+	}
+	return l.wait(ctx, req, path)
+}
+
+// wait blocks until the request is allowed to be sent or ctx is cancelled.
+// The path is the API path of the request relative to the base URL, e.g., "/api/v1/wallet/abc".
+func (l *RateLimiter) wait(ctx context.Context, req *http.Request, path string) error {
+	now := time.Now()
+	l.mu.Lock()
+	wait := l.bucket.reserve(now)
+	endpoint := l.match(req.Method, path)
+	if endpoint != nil {
+		if d := endpoint.bucket.reserve(now); d > wait {
+			wait = d
+		}
+	}
+	l.stats.Requests++
+	if wait <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	l.stats.Delayed++
+	l.stats.Waiting++
+	l.mu.Unlock()
+
+	err := sleep(ctx, wait)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats.Waiting--
+	if err != nil {
+		// The request won't be sent, so its tokens are given back.
+		l.bucket.release()
+		if endpoint != nil {
+			endpoint.bucket.release()
+		}
+		wait = time.Since(now)
+	}
+	l.stats.TotalWait += wait
+	if wait > l.stats.MaxWait {
+		l.stats.MaxWait = wait
+	}
+	return err
+}
+
+// match returns the first endpoint limit matching the request method and the API path relative to the base URL.
+// API v1 paths are matched without the version prefix, API v2 paths are matched with it.
+func (l *RateLimiter) match(method, path string) *endpointLimit {
+	path = strings.Trim(path, "/")
+	path = strings.TrimPrefix(path, "api/v1/")
+	segments := strings.Split(path, "/")
+	for _, e := range l.endpoints {
+		if e.match(method, segments) {
+			return e
+		}
+	}
+	return nil
+}
+
+// endpointLimit is a budget of API endpoints matching the path pattern.
+type endpointLimit struct {
+	pattern []string
+	methods []string
+	bucket  tokenBucket
+}
+
+func (e *endpointLimit) match(method string, segments []string) bool {
+	if len(e.methods) > 0 {
+		found := false
+		for _, m := range e.methods {
+			if strings.EqualFold(m, method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for i, p := range e.pattern {
+		if p == "*" {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if !strings.HasPrefix(p, ":") && p != segments[i] {
+			return false
+		}
+	}
+	return len(e.pattern) == len(segments)
+}
+
+// tokenBucket is refilled with rate tokens per second up to burst tokens.
+// Each request takes a token, and tokens can go negative
+// which means requests have to wait until the debt is refilled.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(r float64, burst int) tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return tokenBucket{
+		rate:   r,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// release gives back a token taken by reserve.
+func (b *tokenBucket) release() {
+	if b.rate <= 0 {
+		return
+	}
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
@@ -0,0 +1,112 @@
+package bitgo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestRateLimiter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":0,"total":0,"start":0,"unspents":[]}`))
+	}))
+	defer srv.Close()
+
+	limiter := bitgo.NewRateLimiter(10, 1)
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRateLimiter(limiter),
+	)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := client.Wallet.Unspents(context.Background(), "", nil, func(*bitgo.UnspentList) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("3 requests at 10 rps should take at least 200ms, took %s", elapsed)
+	}
+
+	stats := limiter.Stats()
+	if stats.Requests != 3 || stats.Delayed != 2 || stats.Waiting != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.TotalWait <= 0 || stats.MaxWait <= 0 {
+		t.Errorf("expected non-zero wait time, got %+v", stats)
+	}
+}
+
+func TestRateLimiterEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	limiter := bitgo.NewRateLimiter(0, 0)
+	limiter.LimitEndpoint("wallet/:id/consolidateunspents", 0.001, 1, http.MethodPut)
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRateLimiter(limiter),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Wallet.Consolidate(ctx, "abc", nil); err != nil {
+		t.Fatal(err)
+	}
+	// The endpoint budget is exhausted, but other endpoints are not limited.
+	req, err := client.NewRequest(ctx, http.MethodGet, "wallet/abc", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = limiter.Wait(ctx, req); err != nil {
+		t.Fatalf("expected wallet endpoint to be unlimited, got %v", err)
+	}
+	if _, err = client.Wallet.Consolidate(ctx, "abc", nil); err != context.DeadlineExceeded {
+		t.Fatalf("expected consolidation to wait until deadline, got %v", err)
+	}
+
+	if stats := limiter.Stats(); stats.Requests != 3 || stats.Delayed != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiterEndpointBasePath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	limiter := bitgo.NewRateLimiter(0, 0)
+	limiter.LimitEndpoint("wallet/:id", 0.001, 1)
+	limiter.LimitEndpoint("api/v2/:coin/wallet/:id", 0.001, 1)
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL+"/bitgo/"),
+		bitgo.WithRateLimiter(limiter),
+	)
+
+	for _, coin := range []string{"", "tbtc"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		for i := 0; i < 2; i++ {
+			req, err := client.NewRequest(ctx, http.MethodGet, "wallet/abc", nil, nil)
+			if coin != "" {
+				req, err = client.NewCoinRequest(ctx, coin, http.MethodGet, "wallet/abc", nil, nil)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.Do(req, nil)
+			if i == 0 && err != nil {
+				t.Fatal(err)
+			}
+			if i == 1 && err != context.DeadlineExceeded {
+				t.Fatalf("expected %q endpoint budget to be exhausted, got %v", req.URL.Path, err)
+			}
+		}
+	}
+}
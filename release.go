@@ -1,5 +0,0 @@
-// +build !debug
-
-package bitgo
-
-func debug(fmt string, args ...interface{}) {}
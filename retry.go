@@ -0,0 +1,176 @@
+package bitgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how Client retries requests which failed due to
+// temporary API errors (50x status codes), rate limiting (429 status code),
+// or transport errors such as a connection reset or a timeout.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is a maximum number of attempts to send a request including the first one.
+	MaxAttempts int
+	// MinBackoff is a delay before the first retry. It doubles on every subsequent retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps a delay between attempts. Zero means there is no cap.
+	// The delay requested by the server in Retry-After header is never shortened,
+	// the request isn't retried if the server asked to wait longer than MaxBackoff.
+	MaxBackoff time.Duration
+	// RetryNonIdempotent allows to retry requests which are not safe to repeat,
+	// e.g., consolidation of unspents. By default only GET, HEAD and OPTIONS requests are retried.
+	// Transport errors are retried only for those methods, because the server might have handled the request.
+	RetryNonIdempotent bool
+	// OnRetry is called before Client waits to send the request again.
+	OnRetry func(RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt which is going to be retried.
+type RetryAttempt struct {
+	// Request is the request which failed.
+	Request *http.Request
+	// Attempt is a number of the failed attempt starting from 1.
+	Attempt int
+	// Err is the error returned by the failed attempt.
+	Err error
+	// Wait is how long Client waits before sending the request again.
+	Wait time.Duration
+}
+
+// DefaultRetryPolicy returns a retry policy which makes up to 5 attempts
+// waiting from 500ms up to 30s between them.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// errBodyNotRewindable is returned when a request must be retried, but its body can't be read again.
+var errBodyNotRewindable = errors.New("bitgo: request body can't be rewound to retry the request")
+
+// backoff returns how long to wait after the failed attempt (starting from 1)
+// and whether the request should be retried at all.
+func (p *RetryPolicy) backoff(req *http.Request, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	var retryAfter time.Duration
+	switch e, ok := err.(Error); {
+	case ok && ((e.IsTemporary() && e.HTTPStatusCode >= 500) || e.IsRateLimited()):
+		if !p.RetryNonIdempotent && !isIdempotent(req.Method) {
+			return 0, false
+		}
+		retryAfter = e.RetryAfter
+	case isTransportError(req, err):
+		if !isIdempotent(req.Method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if p.MaxBackoff > 0 && retryAfter > p.MaxBackoff {
+		return 0, false
+	}
+
+	wait := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	// Equal jitter keeps at least half of the backoff and randomizes the rest to spread out clients which failed at the same time.
+	if half := int64(wait / 2); half > 0 {
+		wait = time.Duration(half + rand.Int63n(half+1))
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if retryAfter > wait {
+		wait = retryAfter
+	}
+	return wait, true
+}
+
+// isTransportError returns true if the request failed due to a network problem before a response was received,
+// e.g., the connection was refused, reset or timed out. Permanent failures such as an unsupported URL scheme
+// or an invalid TLS certificate aren't transport errors, neither is cancellation of the request context.
+func isTransportError(req *http.Request, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED):
+		return true
+	// The server closed the connection without a response or in the middle of it.
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+	return false
+}
+
+// isIdempotent returns true if HTTP method is safe to repeat.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// rewindBody returns a shallow copy of the request with a fresh body so it can be sent again.
+func rewindBody(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, errBodyNotRewindable
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := req.WithContext(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// sleep pauses for duration d or until ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses Retry-After header value which is either
+// a number of seconds or HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		if sec < 0 {
+			return 0
+		}
+		return time.Duration(sec) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,251 @@
+package bitgo_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestRetryTemporaryError(t *testing.T) {
+	filename := filepath.Join("testdata", "unspents.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			http.Error(w, "server is overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	var retries []int
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			OnRetry: func(a bitgo.RetryAttempt) {
+				retries = append(retries, a.Attempt)
+			},
+		}),
+	)
+	err = client.Wallet.Unspents(context.Background(), "", nil, func(list *bitgo.UnspentList) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+	if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("expected retries of attempts [1 2], got %v", retries)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "server is overloaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+		}),
+	)
+	_, err := client.Wallet.Consolidate(context.Background(), "", &bitgo.WalletConsolidateParams{})
+	if e, ok := err.(bitgo.Error); !ok || !e.IsTemporary() {
+		t.Fatalf("expected temporary API error, got %#v", err)
+	}
+	if requests != 1 {
+		t.Errorf("consolidation must not be retried, got %d requests", requests)
+	}
+}
+
+func TestRetryUnexpectedStatus(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, `{"error":"conflict"}`, http.StatusConflict)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: 4,
+			MinBackoff:  time.Millisecond,
+		}),
+	)
+	err := client.Wallet.Unspents(context.Background(), "", nil, func(*bitgo.UnspentList) {})
+	if e, ok := err.(bitgo.Error); !ok || e.Type != bitgo.ErrorTypeAPI || e.HTTPStatusCode != http.StatusConflict {
+		t.Fatalf("expected API error with 409 status code, got %#v", err)
+	}
+	if requests != 1 {
+		t.Errorf("409 must not be retried, got %d requests", requests)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wait time.Duration
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			OnRetry: func(a bitgo.RetryAttempt) {
+				wait = a.Wait
+				// Client shouldn't wait two minutes when the context is cancelled.
+				cancel()
+			},
+		}),
+	)
+	err := client.Wallet.Unspents(ctx, "", nil, nil)
+	if err != context.Canceled {
+		t.Fatalf("expected context cancellation, got %#v", err)
+	}
+	if wait != 2*time.Minute {
+		t.Errorf("expected to wait 2m as requested by server, got %s", wait)
+	}
+}
+
+func TestRetryAfterMaxBackoff(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", r.URL.Query().Get("retry_after"))
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wait time.Duration
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  time.Minute,
+			OnRetry: func(a bitgo.RetryAttempt) {
+				wait = a.Wait
+				cancel()
+			},
+		}),
+	)
+
+	// Retry-After is honoured even though it's longer than the backoff.
+	req, err := client.NewRequest(ctx, http.MethodGet, "wallet", url.Values{"retry_after": {"30"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Do(req, nil); err != context.Canceled {
+		t.Fatalf("expected context cancellation, got %#v", err)
+	}
+	if wait != 30*time.Second {
+		t.Errorf("expected to wait 30s as requested by server, got %s", wait)
+	}
+
+	// The request isn't retried when the server asked to wait longer than MaxBackoff.
+	requests = 0
+	req, err = client.NewRequest(context.Background(), http.MethodGet, "wallet", url.Values{"retry_after": {"120"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Do(req, nil)
+	if e, ok := err.(bitgo.Error); !ok || !e.IsRateLimited() || e.RetryAfter != 2*time.Minute {
+		t.Fatalf("expected rate limit error, got %#v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestRetryTransportError(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// The connection is closed without a response.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts:        3,
+			MinBackoff:         time.Millisecond,
+			RetryNonIdempotent: true,
+		}),
+	)
+	if _, err := client.Wallet.Get(context.Background(), "wallet"); err != nil {
+		t.Fatal(err)
+	}
+	if requests := atomic.LoadInt32(&requests); requests != 2 {
+		t.Errorf("expected the request to be retried, got %d requests", requests)
+	}
+
+	// The server might have handled the request before the connection was closed.
+	atomic.StoreInt32(&requests, 0)
+	if _, err := client.Wallet.Consolidate(context.Background(), "wallet", nil); err == nil {
+		t.Fatal("expected transport error")
+	}
+	if requests := atomic.LoadInt32(&requests); requests != 1 {
+		t.Errorf("consolidation must not be retried, got %d requests", requests)
+	}
+}
+
+func TestRetryPermanentTransportError(t *testing.T) {
+	var retries int
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL("ftp://127.0.0.1/api/v1/"),
+		bitgo.WithRetryPolicy(bitgo.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			OnRetry: func(bitgo.RetryAttempt) {
+				retries++
+			},
+		}),
+	)
+	if _, err := client.Wallet.Get(context.Background(), "wallet"); err == nil {
+		t.Fatal("expected unsupported protocol scheme error")
+	}
+	if retries != 0 {
+		t.Errorf("permanent transport error must not be retried, got %d retries", retries)
+	}
+}
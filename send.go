@@ -0,0 +1,211 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Recipient is an address and an amount of satoshis to send to it.
+type Recipient struct {
+	// The destination address.
+	Address string `json:"address"`
+	// The amount in satoshis to be sent to the address.
+	Amount Amount `json:"amount"`
+}
+
+// SendCoinsParams represents BitGo Express parameters used when sending coins to a single address.
+// For more details, see https://bitgo.github.io/bitgo-docs/#send-coins-to-address.
+type SendCoinsParams struct {
+	// The destination address.
+	Address string `json:"address"`
+	// The amount in satoshis to be sent.
+	Amount Amount `json:"amount"`
+	// Passphrase to decrypt the wallet's private key.
+	WalletPassphrase string `json:"walletPassphrase"`
+	// The desired fee rate for the transaction in satoshis/kilobyte.
+	FeeRate FeeRate `json:"feeRate,omitempty"`
+	// Only choose unspent inputs with at least this many confirmations.
+	MinConfirms int `json:"minConfirms,omitempty"`
+	// Whether MinConfirms also applies to change outputs of previous transactions of the wallet.
+	// Nil means the API default is used.
+	EnforceMinConfirmsForChange *bool `json:"enforceMinConfirmsForChange,omitempty"`
+	// A unique ID of the transaction which prevents sending it twice.
+	SequenceID string `json:"sequenceId,omitempty"`
+	// A note attached to the transaction which is visible to wallet users.
+	Message string `json:"message,omitempty"`
+	// Whether to send a BitGo Instant transaction guaranteed against double spends.
+	Instant bool `json:"instant,omitempty"`
+	// One-time password of the user if the wallet policy requires it.
+	OTP string `json:"otp,omitempty"`
+}
+
+// Validate checks whether the params are accepted by the API.
+func (p *SendCoinsParams) Validate() error {
+	switch {
+	case p == nil:
+		return errors.New("bitgo: send coins params must not be nil")
+	case p.Address == "":
+		return errors.New("bitgo: send coins address must not be empty")
+	case p.Amount <= 0:
+		return fmt.Errorf("bitgo: send coins amount must be positive, got %d", p.Amount)
+	case p.WalletPassphrase == "":
+		return errors.New("bitgo: send coins wallet passphrase must not be empty")
+	}
+	return nil
+}
+
+// SendManyParams represents BitGo Express parameters used when sending coins to multiple addresses.
+// For more details, see https://bitgo.github.io/bitgo-docs/#send-coins-to-multiple-addresses.
+type SendManyParams struct {
+	// The destination addresses and amounts.
+	Recipients []Recipient `json:"recipients"`
+	// Passphrase to decrypt the wallet's private key.
+	WalletPassphrase string `json:"walletPassphrase"`
+	// The desired fee rate for the transaction in satoshis/kilobyte.
+	FeeRate FeeRate `json:"feeRate,omitempty"`
+	// Only choose unspent inputs with at least this many confirmations.
+	MinConfirms int `json:"minConfirms,omitempty"`
+	// Whether MinConfirms also applies to change outputs of previous transactions of the wallet.
+	// Nil means the API default is used.
+	EnforceMinConfirmsForChange *bool `json:"enforceMinConfirmsForChange,omitempty"`
+	// A unique ID of the transaction which prevents sending it twice.
+	SequenceID string `json:"sequenceId,omitempty"`
+	// A note attached to the transaction which is visible to wallet users.
+	Message string `json:"message,omitempty"`
+	// Whether to send a BitGo Instant transaction guaranteed against double spends.
+	Instant bool `json:"instant,omitempty"`
+	// One-time password of the user if the wallet policy requires it.
+	OTP string `json:"otp,omitempty"`
+}
+
+// Validate checks whether the params are accepted by the API.
+func (p *SendManyParams) Validate() error {
+	if p == nil {
+		return errors.New("bitgo: send many params must not be nil")
+	}
+	if err := validateRecipients("send many", p.Recipients); err != nil {
+		return err
+	}
+	if p.WalletPassphrase == "" {
+		return errors.New("bitgo: send many wallet passphrase must not be empty")
+	}
+	return nil
+}
+
+// validateRecipients checks that there is at least one recipient and all of them have addresses and amounts.
+// The op is used as a prefix of error messages, e.g., "send many".
+func validateRecipients(op string, rr []Recipient) error {
+	if len(rr) == 0 {
+		return fmt.Errorf("bitgo: %s recipients must not be empty", op)
+	}
+	for i, r := range rr {
+		switch {
+		case r.Address == "":
+			return fmt.Errorf("bitgo: %s recipient %d address must not be empty", op, i)
+		case r.Amount <= 0:
+			return fmt.Errorf("bitgo: %s recipient %d amount must be positive, got %d", op, i, r.Amount)
+		}
+	}
+	return nil
+}
+
+// SendStatusPendingApproval is a status of a sent transaction which requires approval.
+const SendStatusPendingApproval = "pendingApproval"
+
+// SendResult is a response we get from sendcoins, sendmany and tx/send endpoints.
+// When the transaction requires approval, e.g., it exceeds a spending limit,
+// only Status, PendingApproval, TriggeredPolicy and Message are set.
+type SendResult struct {
+	// Status of the transaction, e.g., "accepted" or "pendingApproval".
+	Status string `json:"status"`
+	// TxID is an id of the transaction.
+	TxID string `json:"hash"`
+	// Tx is the serialized transaction.
+	Tx string `json:"tx"`
+	// Transaction fee in satoshis.
+	Fee Amount `json:"fee"`
+	// The fee rate of the transaction in satoshis/kilobyte.
+	FeeRate FeeRate `json:"feeRate"`
+	// Whether the transaction is a BitGo Instant transaction guaranteed against double spends.
+	Instant bool `json:"instant"`
+	// The ID of BitGo Instant guarantee.
+	InstantID string `json:"instantId"`
+	// Travel rule information sent along with the transaction.
+	TravelInfos []TravelInfo `json:"travelInfos"`
+	// The ID of the pending approval if the transaction requires approval.
+	PendingApproval string `json:"pendingApproval"`
+	// Approval is the pending approval if the transaction requires approval.
+	// Only its ID and State are known when the API returned just the ID, see Error.PendingApproval.
+	Approval *PendingApproval `json:"-"`
+	// The ID of the policy rule which requires approval.
+	TriggeredPolicy string `json:"triggeredPolicy"`
+	// Message explains why the transaction requires approval.
+	Message string `json:"error"`
+}
+
+// RequiresApproval returns true if the transaction wasn't sent because it requires approval.
+func (r *SendResult) RequiresApproval() bool {
+	return r.Status == SendStatusPendingApproval || r.PendingApproval != ""
+}
+
+// SendCoins sends coins from the wallet to a single address using BitGo Express.
+// A transaction which requires approval isn't an error: the result's RequiresApproval returns true.
+func (s *walletService) SendCoins(ctx context.Context, walletID string, params *SendCoinsParams) (*SendResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("wallet/%s/sendcoins", walletID)
+	return s.send(ctx, path, params)
+}
+
+// SendMany sends coins from the wallet to multiple addresses in a single transaction using BitGo Express.
+// A transaction which requires approval isn't an error: the result's RequiresApproval returns true.
+func (s *walletService) SendMany(ctx context.Context, walletID string, params *SendManyParams) (*SendResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("wallet/%s/sendmany", walletID)
+	return s.send(ctx, path, params)
+}
+
+// send posts the params to the Express endpoint and decodes 202 Accepted response as a pending approval.
+func (s *walletService) send(ctx context.Context, path string, bodyParams interface{}) (*SendResult, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, bodyParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var r SendResult
+	_, err = s.client.Do(req, &r)
+	if e, ok := err.(Error); ok && e.IsApprovalRequired() {
+		a, ok := e.PendingApproval()
+		if !ok {
+			return nil, e
+		}
+		// The pending approval field can be either an ID or an object, so it's decoded separately.
+		var v struct {
+			Status          string `json:"status"`
+			TriggeredPolicy string `json:"triggeredPolicy"`
+			Message         string `json:"error"`
+		}
+		_ = json.Unmarshal([]byte(e.Body), &v)
+		r = SendResult{
+			Status:          v.Status,
+			PendingApproval: a.ID,
+			Approval:        a,
+			TriggeredPolicy: v.TriggeredPolicy,
+			Message:         v.Message,
+		}
+		if r.Status == "" {
+			r.Status = SendStatusPendingApproval
+		}
+		return &r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,173 @@
+package bitgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestSendCoins(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte(`{
+			"status": "accepted",
+			"tx": "0100000001",
+			"hash": "3ea7b8b2e6d9c0a8a3cdd1d3e0cf0b7b2e7d8e1bb3b1e2b5e07c5dd2c4c1b8f6",
+			"fee": 2250,
+			"feeRate": 10000,
+			"instant": false,
+			"travelInfos": []
+		}`))
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	enforce := false
+	res, err := client.Wallet.SendCoins(context.Background(), "wallet", &bitgo.SendCoinsParams{
+		Address:                     "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+		Amount:                      100000,
+		WalletPassphrase:            "root",
+		MinConfirms:                 2,
+		EnforceMinConfirmsForChange: &enforce,
+		SequenceID:                  "withdrawal-42",
+		OTP:                         "0000000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequiresApproval() || res.Status != "accepted" || res.Fee != 2250 || res.FeeRate != 10000 ||
+		res.TxID != "3ea7b8b2e6d9c0a8a3cdd1d3e0cf0b7b2e7d8e1bb3b1e2b5e07c5dd2c4c1b8f6" {
+		t.Errorf("unexpected result %#v", res)
+	}
+
+	if want := "/api/v1/wallet/wallet/sendcoins"; path != want {
+		t.Errorf("expected %s path, got %s", want, path)
+	}
+	want := map[string]interface{}{
+		"address":                     "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+		"amount":                      100000.0,
+		"walletPassphrase":            "root",
+		"minConfirms":                 2.0,
+		"enforceMinConfirmsForChange": false,
+		"sequenceId":                  "withdrawal-42",
+		"otp":                         "0000000",
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("expected body %v, got %v", want, body)
+	}
+}
+
+func TestSendManyRequiresApproval(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{
+			"error": "exceeds a spending limit",
+			"pendingApproval": "55e8a1a5df8380e0e30e20c7",
+			"triggeredPolicy": "daily-limit",
+			"status": "pendingApproval"
+		}`))
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	res, err := client.Wallet.SendMany(context.Background(), "wallet", &bitgo.SendManyParams{
+		Recipients: []bitgo.Recipient{
+			{Address: "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm", Amount: 100000},
+			{Address: "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4", Amount: 200000},
+		},
+		WalletPassphrase: "root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.RequiresApproval() {
+		t.Fatal("expected the transaction to require approval")
+	}
+	if res.PendingApproval != "55e8a1a5df8380e0e30e20c7" || res.TriggeredPolicy != "daily-limit" || res.Message != "exceeds a spending limit" {
+		t.Errorf("unexpected result %#v", res)
+	}
+}
+
+func TestSendManyRequiresApprovalObject(t *testing.T) {
+	tests := map[string]string{
+		"nested object": `{
+			"error": "exceeds a spending limit",
+			"pendingApproval": {"id": "55e8a1a5df8380e0e30e20c7", "state": "pending", "approvalsRequired": 1},
+			"triggeredPolicy": "daily-limit"
+		}`,
+		"top-level object": `{
+			"id": "55e8a1a5df8380e0e30e20c7",
+			"state": "pending",
+			"approvalsRequired": 1
+		}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(body))
+			}))
+			defer srv.Close()
+
+			client := bitgo.NewClient(
+				bitgo.WithBaseURL(srv.URL),
+			)
+			res, err := client.Wallet.SendCoins(context.Background(), "wallet", &bitgo.SendCoinsParams{
+				Address:          "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm",
+				Amount:           100000,
+				WalletPassphrase: "root",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.RequiresApproval() || res.Status != bitgo.SendStatusPendingApproval {
+				t.Fatalf("expected the transaction to require approval, got %#v", res)
+			}
+			if res.PendingApproval != "55e8a1a5df8380e0e30e20c7" {
+				t.Errorf("expected pending approval ID, got %q", res.PendingApproval)
+			}
+			if res.Approval == nil || res.Approval.State != bitgo.ApprovalStatePending || res.Approval.ApprovalsRequired != 1 {
+				t.Errorf("unexpected pending approval %#v", res.Approval)
+			}
+		})
+	}
+}
+
+func TestSendParamsValidate(t *testing.T) {
+	tests := []struct {
+		params interface{ Validate() error }
+		valid  bool
+	}{
+		{&bitgo.SendCoinsParams{Address: "a", Amount: 1, WalletPassphrase: "root"}, true},
+		{&bitgo.SendCoinsParams{Address: "a", Amount: 0, WalletPassphrase: "root"}, false},
+		{&bitgo.SendCoinsParams{Amount: 1, WalletPassphrase: "root"}, false},
+		{&bitgo.SendCoinsParams{Address: "a", Amount: 1}, false},
+		{&bitgo.SendManyParams{Recipients: []bitgo.Recipient{{Address: "a", Amount: 1}}, WalletPassphrase: "root"}, true},
+		{&bitgo.SendManyParams{WalletPassphrase: "root"}, false},
+		{&bitgo.SendManyParams{Recipients: []bitgo.Recipient{{Address: "a", Amount: -1}}, WalletPassphrase: "root"}, false},
+		{(*bitgo.SendCoinsParams)(nil), false},
+		{(*bitgo.SendManyParams)(nil), false},
+	}
+	for _, test := range tests {
+		err := test.params.Validate()
+		if (err == nil) != test.valid {
+			t.Errorf("Validate(%#v) = %v, want valid %v", test.params, err, test.valid)
+		}
+	}
+}
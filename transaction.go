@@ -0,0 +1,183 @@
+package bitgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Transaction is a wallet transaction as seen in the wallet's history.
+// For more details, see https://bitgo.github.io/bitgo-docs/#get-wallet-transaction.
+type Transaction struct {
+	// The hash of the transaction.
+	ID string `json:"id"`
+	// The hash of the transaction without its signatures which doesn't change on malleation.
+	NormalizedHash string `json:"normalizedHash"`
+	// The time the transaction was first seen.
+	Date time.Time `json:"date"`
+	// The hash of the block the transaction was included in.
+	BlockHash string `json:"blockhash"`
+	// The height of the block the transaction was included in.
+	Height int `json:"height"`
+	// Number of blocks seen on and after the transaction was included in a block.
+	Confirmations int `json:"confirmations"`
+	// Transaction fee in satoshis.
+	Fee Amount `json:"fee"`
+	// Whether the transaction is not confirmed yet.
+	Pending bool `json:"pending"`
+	// Whether the transaction is a BitGo Instant transaction guaranteed against double spends.
+	Instant bool `json:"instant"`
+	// The serialized transaction (in hex format).
+	Hex string `json:"hex"`
+	// The comment attached to the transaction by the sender.
+	Comment string `json:"comment"`
+	// The unique ID which was set when the transaction was sent.
+	SequenceID string `json:"sequenceId"`
+	// The ID of the pending approval if the transaction requires approval.
+	PendingApproval string `json:"pendingApproval"`
+	// Transaction inputs.
+	Inputs []TxInput `json:"inputs"`
+	// Transaction outputs.
+	Outputs []TxOutput `json:"outputs"`
+	// Net changes of address balances caused by the transaction.
+	Entries []TxEntry `json:"entries"`
+}
+
+// TxInput is a transaction input which spends an output of a previous transaction.
+type TxInput struct {
+	// The hash of the previous transaction.
+	PreviousHash string `json:"previousHash"`
+	// The index of the spent output in the previous transaction.
+	PreviousOutputIndex int `json:"previousOutputIndex"`
+}
+
+// TxOutput is a transaction output.
+type TxOutput struct {
+	// The index of the output in the transaction.
+	Vout int `json:"vout"`
+	// The value of the output in satoshis.
+	Value Amount `json:"value"`
+	// The address the output pays to.
+	Account string `json:"account"`
+	// Whether the address belongs to the wallet.
+	IsMine bool `json:"isMine"`
+	// The chain of the wallet address if the output is mine.
+	Chain Chain `json:"chain"`
+	// The index of the wallet address on its chain if the output is mine.
+	ChainIndex int `json:"chainIndex"`
+}
+
+// TxEntry is a net change of an address balance caused by a transaction.
+type TxEntry struct {
+	// The address.
+	Account string `json:"account"`
+	// The change of the address balance in satoshis which is negative for spends.
+	Value Amount `json:"value"`
+}
+
+// TransactionList is a list of transactions as retrieved from a list endpoint.
+type TransactionList struct {
+	ListMeta
+	Transactions []Transaction `json:"transactions"`
+}
+
+// TransactionsParams represents query parameters used when listing wallet transactions.
+// For more details, see https://bitgo.github.io/bitgo-docs/#list-wallet-transactions.
+type TransactionsParams struct {
+	// Max number of results to return in a single call (defaults to 25, max is 500).
+	Limit int
+	// The starting index number to list from (defaults to 0).
+	Skip int
+	// Only include transactions at or above this block height.
+	MinHeight int
+	// Omit inputs and outputs from the results to make them smaller.
+	Compact bool
+	// Only include transactions seen at or after this time.
+	MinDate time.Time
+	// Only include transactions seen before this time.
+	MaxDate time.Time
+}
+
+// values returns the params encoded as a query string, zero values are omitted.
+func (p *TransactionsParams) values() url.Values {
+	v := url.Values{}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Skip > 0 {
+		v.Set("skip", strconv.Itoa(p.Skip))
+	}
+	if p.MinHeight > 0 {
+		v.Set("minHeight", strconv.Itoa(p.MinHeight))
+	}
+	if p.Compact {
+		v.Set("compact", "true")
+	}
+	if !p.MinDate.IsZero() {
+		v.Set("minDate", p.MinDate.UTC().Format(time.RFC3339))
+	}
+	if !p.MaxDate.IsZero() {
+		v.Set("maxDate", p.MaxDate.UTC().Format(time.RFC3339))
+	}
+	return v
+}
+
+// Transactions returns a Pager to iterate over transactions of the wallet, the latest first.
+// Nil params means API defaults are used. Pagination starts from params.Skip.
+func (s *walletService) Transactions(walletID string, params *TransactionsParams) *Pager[Transaction] {
+	path := fmt.Sprintf("wallet/%s/tx", walletID)
+	var p TransactionsParams
+	if params != nil {
+		p = *params
+	}
+
+	return NewPager(p.Skip, func(ctx context.Context, skip int) (*Page[Transaction], error) {
+		q := p
+		q.Skip = skip
+		req, err := s.client.NewRequest(ctx, http.MethodGet, path, q.values(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		v := TransactionList{}
+		if _, err = s.client.Do(req, &v); err != nil {
+			return nil, err
+		}
+		return &Page[Transaction]{ListMeta: v.ListMeta, Items: v.Transactions}, nil
+	})
+}
+
+// Transaction gets the wallet transaction by its hash.
+func (s *walletService) Transaction(ctx context.Context, walletID, txHash string) (*Transaction, error) {
+	path := fmt.Sprintf("wallet/%s/tx/%s", walletID, txHash)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Transaction
+	if _, err = s.client.Do(req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// TransactionBySequenceID gets the wallet transaction by the sequence ID set when it was sent.
+func (s *walletService) TransactionBySequenceID(ctx context.Context, walletID, sequenceID string) (*Transaction, error) {
+	path := fmt.Sprintf("wallet/%s/tx/sequence/%s", walletID, url.PathEscape(sequenceID))
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		Transaction Transaction `json:"transaction"`
+	}
+	if _, err = s.client.Do(req, &v); err != nil {
+		return nil, err
+	}
+	return &v.Transaction, nil
+}
@@ -0,0 +1,129 @@
+package bitgo_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestTransaction(t *testing.T) {
+	filename := filepath.Join("testdata", "transaction.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/api/v1/wallet/wallet/tx/sequence/withdrawal-42" {
+			fmt.Fprintf(w, `{"transaction":%s}`, content)
+			return
+		}
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	want := bitgo.Transaction{
+		ID:              "3ea7b8b2e6d9c0a8a3cdd1d3e0cf0b7b2e7d8e1bb3b1e2b5e07c5dd2c4c1b8f6",
+		NormalizedHash:  "c8a21b0e5f9a1d8e3b7c6f2a4d9e0b1c5a8f7e3d2b6c9a0f1e4d7b8c3a5f2e9d",
+		Date:            time.Date(2015, 9, 18, 21, 58, 11, 620000000, time.UTC),
+		BlockHash:       "00000000000002d6a3f0e7ad0b9f6d5c2c8e2f1b7b5a5cbe5c4a1c6f9b2b8e0d",
+		Height:          570611,
+		Confirmations:   3474,
+		Fee:             10000,
+		Hex:             "0100000001f6b8c1c4d25d7ce0b5e2b1b3bbe1d8e7b2b70bcfe0d3d1cda3a8c0d9e6b2b8a7ea0000000000ffffffff0200e1f505000000001976a914000000000000000000000000000000000000000088ac",
+		Comment:         "Withdrawal #42",
+		SequenceID:      "withdrawal-42",
+		PendingApproval: "55e8a1a5df8380e0e30e20c7",
+		Inputs: []bitgo.TxInput{
+			{PreviousHash: "3246b59fcec99c81e5f59522327b632f5c54e4da42ccb512550ed91a3f9b5ce6"},
+		},
+		Outputs: []bitgo.TxOutput{
+			{Vout: 0, Value: 100000000, Account: "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm"},
+			{Vout: 1, Value: 78173176932, Account: "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4", IsMine: true, Chain: bitgo.ChainP2SHP2WSHChange, ChainIndex: 4},
+		},
+		Entries: []bitgo.TxEntry{
+			{Account: "2N26EdwtVNQe6P9QkVgLHGhoWtU5W98ohNB", Value: -78273186932},
+			{Account: "2MvfC3e6njdTXqWDfGvNUqDs8kwPXLu5WY4", Value: 78173176932},
+			{Account: "mhKzZrTqBmSC3ezEmWEvK9JzbmVhGqC1Nm", Value: 100000000},
+		},
+	}
+
+	byHash, err := client.Wallet.Transaction(context.Background(), "wallet", want.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bySequence, err := client.Wallet.TransactionBySequenceID(context.Background(), "wallet", want.SequenceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range []*bitgo.Transaction{byHash, bySequence} {
+		if !got.Date.Equal(want.Date) {
+			t.Fatalf("should be %s, not %s", want.Date, got.Date)
+		}
+		got.Date = want.Date
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("should be %#v, not %#v", want, *got)
+		}
+	}
+
+	wantPaths := []string{
+		"/api/v1/wallet/wallet/tx/" + want.ID,
+		"/api/v1/wallet/wallet/tx/sequence/withdrawal-42",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("expected paths %q, got %q", wantPaths, paths)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		skip := r.URL.Query().Get("skip")
+		if skip == "" {
+			skip = "0"
+		}
+		fmt.Fprintf(w, `{"count":1,"start":%s,"total":2,"transactions":[{"id":"tx%s","fee":1}]}`, skip, skip)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	params := bitgo.TransactionsParams{
+		Limit:     1,
+		MinHeight: 570000,
+		Compact:   true,
+		MinDate:   time.Date(2015, 9, 1, 0, 0, 0, 0, time.UTC),
+		MaxDate:   time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var got []string
+	for tx, err := range client.Wallet.Transactions("wallet", &params).All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, tx.ID)
+	}
+	if want := []string{"tx0", "tx1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	wantQueries := []string{
+		"compact=true&limit=1&maxDate=2015-10-01T00%3A00%3A00Z&minDate=2015-09-01T00%3A00%3A00Z&minHeight=570000",
+		"compact=true&limit=1&maxDate=2015-10-01T00%3A00%3A00Z&minDate=2015-09-01T00%3A00%3A00Z&minHeight=570000&skip=1",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("expected queries %q, got %q", wantQueries, queries)
+	}
+}
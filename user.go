@@ -0,0 +1,281 @@
+package bitgo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// UserService communicates with user authentication API endpoints.
+// See bitgomock package for its mock implementation.
+type UserService interface {
+	// Login authenticates the user and stores the session token in the Client.
+	Login(ctx context.Context, params *LoginParams) (*LoginResult, error)
+	// Logout ends the current session and removes its token from the Client.
+	Logout(ctx context.Context) error
+	// Session returns the current session.
+	Session(ctx context.Context) (*Session, error)
+	// Unlock unlocks the current session for the duration to allow sending coins.
+	Unlock(ctx context.Context, otp string, duration time.Duration) (*Session, error)
+	// Me returns the authenticated user.
+	Me(ctx context.Context) (*User, error)
+	// CreateAccessToken creates a long-lived access token of the user.
+	CreateAccessToken(ctx context.Context, params *AccessTokenParams) (*AccessToken, error)
+	// AccessTokens returns access tokens of the user.
+	AccessTokens(ctx context.Context) ([]AccessToken, error)
+	// RevokeAccessToken revokes the access token by its ID.
+	RevokeAccessToken(ctx context.Context, tokenID string) error
+}
+
+// userService communicates with user authentication API endpoints.
+type userService struct {
+	client *Client
+}
+
+var _ UserService = (*userService)(nil)
+
+// User is a BitGo user.
+// For more details, see https://bitgo.github.io/bitgo-docs/#current-user-profile.
+type User struct {
+	// The ID of the user.
+	ID string `json:"id"`
+	// The username of the user which is usually the email.
+	Username string `json:"username"`
+	// The name of the user.
+	Name UserName `json:"name"`
+	// The email of the user.
+	Email UserEmail `json:"email"`
+	// The phone of the user.
+	Phone UserPhone `json:"phone"`
+	// Whether the user account is active.
+	IsActive bool `json:"isActive"`
+	// Devices which generate one-time passwords for the user.
+	OTPDevices []OTPDevice `json:"otpDevices"`
+}
+
+// UserName is a name of a user.
+type UserName struct {
+	Full  string `json:"full"`
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+// UserEmail is an email of a user.
+type UserEmail struct {
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+}
+
+// UserPhone is a phone of a user.
+type UserPhone struct {
+	Phone    string `json:"phone"`
+	Verified bool   `json:"verified"`
+}
+
+// OTPDevice is a device which generates one-time passwords, e.g., Authy or Yubikey.
+type OTPDevice struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// Session is a user session tied to an access token.
+// For more details, see https://bitgo.github.io/bitgo-docs/#session-information.
+type Session struct {
+	// The client the session was created by, e.g., "bitgo".
+	Client string `json:"client"`
+	// The ID of the user.
+	User string `json:"user"`
+	// Scopes granted to the session token.
+	Scope []Scope `json:"scope"`
+	// The time the session was created.
+	Created time.Time `json:"created"`
+	// The time the session expires.
+	Expires time.Time `json:"expires"`
+	// The origin the session was created from.
+	Origin string `json:"origin"`
+	// The unlock of the session if it's unlocked, otherwise nil.
+	Unlock *SessionUnlock `json:"unlock"`
+}
+
+// IsUnlocked returns true if the session is unlocked at the time t.
+func (s *Session) IsUnlocked(t time.Time) bool {
+	return s.Unlock != nil && t.Before(s.Unlock.Expires)
+}
+
+// SessionUnlock describes an unlock of a session which allows sending coins.
+type SessionUnlock struct {
+	// The time the session was unlocked.
+	Time time.Time `json:"time"`
+	// The time the unlock expires.
+	Expires time.Time `json:"expires"`
+	// Number of transactions sent since the unlock.
+	TxCount int `json:"txCount"`
+	// Total amount in satoshis sent since the unlock.
+	TxValue Amount `json:"txValue"`
+}
+
+// LoginParams represents parameters used when a user logs in.
+// For more details, see https://bitgo.github.io/bitgo-docs/#user-authentication.
+type LoginParams struct {
+	// The email of the user.
+	Email string
+	// The password of the user. It's never sent, an HMAC of the password is sent instead.
+	Password string
+	// One-time password of the user.
+	OTP string
+	// Whether the session can be extended beyond its initial duration.
+	Extensible bool
+}
+
+// Validate checks whether the params are accepted by the API.
+func (p *LoginParams) Validate() error {
+	switch {
+	case p == nil:
+		return errors.New("bitgo: login params must not be nil")
+	case p.Email == "":
+		return errors.New("bitgo: login email must not be empty")
+	case p.Password == "":
+		return errors.New("bitgo: login password must not be empty")
+	}
+	return nil
+}
+
+// LoginResult is a response we get from user/login endpoint.
+type LoginResult struct {
+	// The session access token.
+	AccessToken string `json:"access_token"`
+	// The type of the token, e.g., "bearer".
+	TokenType string `json:"token_type"`
+	// Number of seconds until the token expires.
+	ExpiresIn int `json:"expires_in"`
+	// The authenticated user.
+	User User `json:"user"`
+}
+
+// passwordHMAC returns the password as BitGo expects it:
+// HMAC-SHA256 of the password keyed with the lowercase email in hex format.
+func passwordHMAC(email, password string) string {
+	mac := hmac.New(sha256.New, []byte(email))
+	mac.Write([]byte(password))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Login authenticates the user and stores the session token in the Client,
+// so the following requests are authenticated with it instead of the access token.
+func (s *userService) Login(ctx context.Context, params *LoginParams) (*LoginResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(params.Email)
+	body := struct {
+		Email      string `json:"email"`
+		Password   string `json:"password"`
+		OTP        string `json:"otp,omitempty"`
+		Extensible bool   `json:"extensible,omitempty"`
+	}{
+		Email:      email,
+		Password:   passwordHMAC(email, params.Password),
+		OTP:        params.OTP,
+		Extensible: params.Extensible,
+	}
+	// Login failed due to missing OTP must not trigger an unlock, see WithOTPProvider.
+	ctx = context.WithValue(ctx, noUnlockKey{}, true)
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "user/login", nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var r LoginResult
+	if _, err = s.client.Do(req, &r); err != nil {
+		return nil, err
+	}
+	if r.AccessToken == "" {
+		return nil, errors.New("bitgo: login response has no access token")
+	}
+	s.client.SetSessionToken(r.AccessToken)
+	return &r, nil
+}
+
+// Logout ends the current session and removes its token from the Client.
+func (s *userService) Logout(ctx context.Context) error {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "user/logout", nil, nil)
+	if err != nil {
+		return err
+	}
+	if _, err = s.client.Do(req, nil); err != nil {
+		return err
+	}
+	s.client.SetSessionToken("")
+	return nil
+}
+
+// Session returns the current session.
+func (s *userService) Session(ctx context.Context) (*Session, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "user/session", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		Session Session `json:"session"`
+	}
+	if _, err = s.client.Do(req, &v); err != nil {
+		return nil, err
+	}
+	return &v.Session, nil
+}
+
+// Unlock unlocks the current session for the duration to allow sending coins.
+// The duration is rounded down to seconds, zero means the API default is used (10 minutes).
+func (s *userService) Unlock(ctx context.Context, otp string, duration time.Duration) (*Session, error) {
+	if otp == "" {
+		return nil, errors.New("bitgo: unlock otp must not be empty")
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("bitgo: unlock duration must not be negative, got %s", duration)
+	}
+	body := struct {
+		OTP      string `json:"otp"`
+		Duration int    `json:"duration,omitempty"`
+	}{
+		OTP:      otp,
+		Duration: int(duration / time.Second),
+	}
+	// Failed unlock must not trigger another unlock, see WithOTPProvider.
+	ctx = context.WithValue(ctx, noUnlockKey{}, true)
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "user/unlock", nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		Session Session `json:"session"`
+	}
+	if _, err = s.client.Do(req, &v); err != nil {
+		return nil, err
+	}
+	return &v.Session, nil
+}
+
+// Me returns the authenticated user.
+func (s *userService) Me(ctx context.Context) (*User, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "user/me", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		User User `json:"user"`
+	}
+	if _, err = s.client.Do(req, &v); err != nil {
+		return nil, err
+	}
+	return &v.User, nil
+}
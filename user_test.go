@@ -0,0 +1,187 @@
+package bitgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marselester/bitgo-v1"
+)
+
+func TestUserLogin(t *testing.T) {
+	login, err := ioutil.ReadFile(filepath.Join("testdata", "login.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const sessionToken = "v2x8d2d8b1d35b6e4f6ee01ab6d1b1fa0e9c06f1c0a8c7e4c38d2e7a1a9d0f4a2b1"
+
+	var (
+		loginBody map[string]interface{}
+		auth      []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/user/login":
+			json.NewDecoder(r.Body).Decode(&loginBody)
+			w.Write(login)
+		case "/api/v1/user/me":
+			var v map[string]json.RawMessage
+			json.Unmarshal(login, &v)
+			w.Write([]byte(`{"user":` + string(v["user"]) + `}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+		bitgo.WithAccesToken("swordfish"),
+	)
+	ctx := context.Background()
+	res, err := client.User.Login(ctx, &bitgo.LoginParams{
+		Email:    "Alice@Example.com",
+		Password: "correct horse",
+		OTP:      "0000000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AccessToken != sessionToken || res.ExpiresIn != 3600 || res.User.Username != "alice@example.com" {
+		t.Errorf("unexpected login result %#v", res)
+	}
+	if client.SessionToken() != sessionToken {
+		t.Errorf("expected the session token to be stored, got %q", client.SessionToken())
+	}
+	wantBody := map[string]interface{}{
+		"email":    "alice@example.com",
+		"password": "9449d739df79bbdae40700f7572fd50e906c8c6ef942473bf08d0df0d84ce929",
+		"otp":      "0000000",
+	}
+	if !reflect.DeepEqual(loginBody, wantBody) {
+		t.Errorf("expected %v login body, got %v", wantBody, loginBody)
+	}
+
+	me, err := client.User.Me(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bitgo.User{
+		ID:       "55e8a1a5df8380e0e30e20c4",
+		Username: "alice@example.com",
+		Name:     bitgo.UserName{Full: "Alice Smith", First: "Alice", Last: "Smith"},
+		Email:    bitgo.UserEmail{Email: "alice@example.com", Verified: true},
+		Phone:    bitgo.UserPhone{Phone: "+14155550100", Verified: true},
+		IsActive: true,
+		OTPDevices: []bitgo.OTPDevice{
+			{ID: "55e8a1a5df8380e0e30e20d1", Type: "totp", Label: "phone"},
+		},
+	}
+	if !reflect.DeepEqual(*me, want) {
+		t.Errorf("should be %#v, not %#v", want, *me)
+	}
+
+	if err = client.User.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if client.SessionToken() != "" {
+		t.Errorf("expected the session token to be removed, got %q", client.SessionToken())
+	}
+	if _, err = client.Wallet.Get(ctx, "wallet"); err != nil {
+		t.Fatal(err)
+	}
+
+	wantAuth := []string{
+		"POST /api/v1/user/login Bearer swordfish",
+		"GET /api/v1/user/me Bearer " + sessionToken,
+		"GET /api/v1/user/logout Bearer " + sessionToken,
+		"GET /api/v1/wallet/wallet Bearer swordfish",
+	}
+	if !reflect.DeepEqual(auth, wantAuth) {
+		t.Errorf("expected %v, got %v", wantAuth, auth)
+	}
+}
+
+func TestUserLoginValidate(t *testing.T) {
+	client := bitgo.NewClient(bitgo.WithBaseURL("http://0.0.0.0:1"))
+	for _, p := range []*bitgo.LoginParams{
+		{Password: "correct horse"},
+		{Email: "alice@example.com"},
+		nil,
+	} {
+		if _, err := client.User.Login(context.Background(), p); err == nil {
+			t.Errorf("expected validation error for %+v", p)
+		}
+	}
+}
+
+func TestUserSession(t *testing.T) {
+	content, err := ioutil.ReadFile(filepath.Join("testdata", "session.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+r.URL.Path+" "+string(b))
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(bitgo.WithBaseURL(srv.URL))
+	client.SetSessionToken("v2xsession")
+	ctx := context.Background()
+	want := bitgo.Session{
+		Client:  "bitgo",
+		User:    "55e8a1a5df8380e0e30e20c4",
+		Scope:   []bitgo.Scope{bitgo.ScopeOpenID, bitgo.ScopeProfile, bitgo.ScopeWalletSpendAll, bitgo.ScopeWalletViewAll},
+		Created: time.Date(2015, 9, 3, 19, 55, 12, 11000000, time.UTC),
+		Expires: time.Date(2015, 9, 3, 20, 55, 12, 11000000, time.UTC),
+		Origin:  "test.bitgo.com",
+		Unlock: &bitgo.SessionUnlock{
+			Time:    time.Date(2015, 9, 3, 19, 56, 0, 0, time.UTC),
+			Expires: time.Date(2015, 9, 3, 20, 6, 0, 0, time.UTC),
+			TxCount: 1,
+			TxValue: 250000,
+		},
+	}
+
+	got, err := client.User.Session(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlocked, err := client.User.Unlock(ctx, "0000000", 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []*bitgo.Session{got, unlocked} {
+		if !reflect.DeepEqual(*s, want) {
+			t.Errorf("should be %#v, not %#v", want, *s)
+		}
+	}
+	if !got.IsUnlocked(want.Unlock.Time) || got.IsUnlocked(want.Unlock.Expires) {
+		t.Errorf("expected the session to be unlocked until %s", want.Unlock.Expires)
+	}
+
+	wantBodies := []string{
+		"GET /api/v1/user/session ",
+		`POST /api/v1/user/unlock {"otp":"0000000","duration":600}`,
+	}
+	if !reflect.DeepEqual(bodies, wantBodies) {
+		t.Errorf("expected %v, got %v", wantBodies, bodies)
+	}
+
+	if _, err = client.User.Unlock(ctx, "", 0); err == nil {
+		t.Errorf("expected empty OTP error")
+	}
+	if _, err = client.User.Unlock(ctx, "0000000", -time.Second); err == nil {
+		t.Errorf("expected negative duration error")
+	}
+}
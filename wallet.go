@@ -2,28 +2,74 @@ package bitgo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
+// WalletService communicates with the wallet API endpoints.
+// See bitgomock package for its mock implementation.
+type WalletService interface {
+	// List returns a Pager to iterate over wallets of the user.
+	List(params *WalletListParams) *Pager[Wallet]
+	// Get gets a wallet by its ID.
+	Get(ctx context.Context, walletID string) (*Wallet, error)
+	// CreateAddress creates a new address on the chain of the wallet.
+	CreateAddress(ctx context.Context, walletID string, chain Chain) (*Address, error)
+	// Addresses returns a Pager to iterate over addresses of the wallet.
+	Addresses(walletID string, params *AddressesParams) *Pager[Address]
+	// Address gets the wallet address with its balance and totals.
+	Address(ctx context.Context, walletID, address string) (*Address, error)
+	// Transactions returns a Pager to iterate over transactions of the wallet.
+	Transactions(walletID string, params *TransactionsParams) *Pager[Transaction]
+	// Transaction gets the wallet transaction by its hash.
+	Transaction(ctx context.Context, walletID, txHash string) (*Transaction, error)
+	// TransactionBySequenceID gets the wallet transaction by the sequence ID set when it was sent.
+	TransactionBySequenceID(ctx context.Context, walletID, sequenceID string) (*Transaction, error)
+	// Unspents gets a list of unspent transaction outputs (UTXOs) for a wallet.
+	Unspents(ctx context.Context, walletID string, params *UnspentsParams, f func(*UnspentList)) error
+	// UnspentsPager returns a Pager to iterate over unspent transaction outputs (UTXOs) of a wallet.
+	UnspentsPager(walletID string, params *UnspentsParams) *Pager[Unspent]
+	// CreateTransaction builds an unsigned transaction using BitGo Express.
+	CreateTransaction(ctx context.Context, walletID string, params *CreateTransactionParams) (*UnsignedTx, error)
+	// SignTransaction signs the unsigned transaction using BitGo Express.
+	SignTransaction(ctx context.Context, walletID string, params *SignTransactionParams) (*SignedTx, error)
+	// SendTransaction broadcasts the signed transaction.
+	SendTransaction(ctx context.Context, params *SendTransactionParams) (*SendResult, error)
+	// SendCoins sends coins from the wallet to a single address using BitGo Express.
+	SendCoins(ctx context.Context, walletID string, params *SendCoinsParams) (*SendResult, error)
+	// SendMany sends coins from the wallet to multiple addresses using BitGo Express.
+	SendMany(ctx context.Context, walletID string, params *SendManyParams) (*SendResult, error)
+	// Consolidate coalesces UTXOs currently held in a wallet to a smaller number.
+	Consolidate(ctx context.Context, walletID string, bodyParams *WalletConsolidateParams) ([]TxInfo, error)
+}
+
 // walletService communicates with the wallet API endpoints.
 type walletService struct {
 	client *Client
 }
 
+var _ WalletService = (*walletService)(nil)
+
 // Satoshi is the smallest unit of bitcoin.
 const Satoshi = 0.00000001
 
 // ToBitcoins converts satoshis to bitcoins.
+//
+// Deprecated: use Amount.BTC or Amount.Format.
 func ToBitcoins(amount int64) float64 {
 	return float64(amount) * Satoshi
 }
 
-// ToSatoshis converts bitcoins to satoshis.
+// ToSatoshis converts bitcoins to satoshis rounding to the nearest satoshi.
+//
+// Deprecated: use ParseAmount to parse exact amounts.
 func ToSatoshis(amount float64) int64 {
-	return int64(amount / Satoshi)
+	return int64(math.Round(amount / Satoshi))
 }
 
 // Unspent is an unspent transaction output (UTXO).
@@ -35,11 +81,13 @@ type Unspent struct {
 	// The index of the unspent input from tx_hash.
 	TxOutputN int `json:"tx_output_n"`
 	// The value, in satoshis of the unspent input.
-	Value int64 `json:"value"`
+	Value Amount `json:"value"`
 	// Output script hash (in hex format).
 	Script string `json:"script"`
 	// The redeem script.
 	RedeemScript string `json:"redeemScript"`
+	// The witness script of SegWit unspents.
+	WitnessScript string `json:"witnessScript,omitempty"`
 	// The BIP32 path of the unspent output relative to the wallet.
 	ChainPath string `json:"chainPath"`
 	// Number of blocks seen on and after the unspent transaction was included in a block.
@@ -49,57 +97,172 @@ type Unspent struct {
 	IsChange bool `json:"isChange"`
 	// Boolean indicating if this unspent can be used to create a BitGo Instant transaction guaranteed against double spends.
 	Instant bool `json:"instant"`
+	// The height of the block the unspent transaction was included in.
+	BlockHeight int `json:"blockHeight"`
+	// The time the unspent transaction was first seen.
+	Date time.Time `json:"date"`
+	// The ID of the wallet the unspent belongs to.
+	Wallet string `json:"wallet"`
+	// Forked chains, e.g., "btg", where the unspent is protected against transaction replay.
+	ReplayProtection []string `json:"replayProtection"`
+	// Raw is the unspent JSON object as returned by the API.
+	// It gives access to fields which are not modelled yet.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the unspent and keeps its raw JSON.
+func (u *Unspent) UnmarshalJSON(b []byte) error {
+	type unspent Unspent
+	if err := json.Unmarshal(b, (*unspent)(u)); err != nil {
+		return err
+	}
+	u.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// OutPoint returns the unspent's transaction hash and output index, e.g., "3246b59f...5ce6:0",
+// which uniquely identifies the unspent.
+func (u Unspent) OutPoint() string {
+	return u.TxHash + ":" + strconv.Itoa(u.TxOutputN)
 }
 
 // ListMeta is a pagination metadata.
 type ListMeta struct {
 	// Count is a number of records returned in API response, e.g., 2.
-	Count int
+	Count int `json:"count"`
 	// Total is a total number of records, e.g., 5000.
-	Total int
+	Total int `json:"total"`
 	// Start is a starting index number to list from, e.g., 0.
-	Start int
+	Start int `json:"start"`
 }
 
 // UnspentList is a list of unspents as retrieved from a list endpoint.
 type UnspentList struct {
 	ListMeta
 	Unspents []Unspent `json:"unspents"`
+	// PendingTransactions indicates that the wallet has pending transactions
+	// which may spend some of the unspents.
+	PendingTransactions bool `json:"pendingTransactions"`
+}
+
+// UnspentsParams represents query parameters used when listing unspents.
+// For more details, see https://bitgo.github.io/bitgo-docs/#list-wallet-unspents.
+type UnspentsParams struct {
+	// The API will attempt to return enough unspents to accumulate to at least this amount of satoshis.
+	Target Amount
+	// Only include unspents with at least this many confirmations.
+	MinConfirms int
+	// Only include unspents that are at least this many satoshis.
+	MinSize Amount
+	// Max number of results to return in a single call (defaults to 100, max is 250).
+	Limit int
+	// The starting index number to list from (defaults to 0).
+	Skip int
+	// Whether to include SegWit unspents. Nil means the API default is used.
+	Segwit *bool
+	// Only include unspents which can be used to create a BitGo Instant transaction.
+	Instant bool
+}
+
+// MaxUnspentsLimit is the max number of unspents returned in a single call.
+const MaxUnspentsLimit = 250
+
+// Validate checks whether the params are accepted by the API.
+func (p *UnspentsParams) Validate() error {
+	switch {
+	case p.Limit < 0:
+		return fmt.Errorf("bitgo: unspents limit must be between 1 and %d, or 0 for API default, got %d", MaxUnspentsLimit, p.Limit)
+	case p.Limit > MaxUnspentsLimit:
+		return fmt.Errorf("bitgo: unspents limit must not exceed %d, got %d", MaxUnspentsLimit, p.Limit)
+	case p.Skip < 0:
+		return fmt.Errorf("bitgo: unspents skip must not be negative, got %d", p.Skip)
+	case p.MinConfirms < 0:
+		return fmt.Errorf("bitgo: unspents min confirms must not be negative, got %d", p.MinConfirms)
+	case p.Target < 0:
+		return fmt.Errorf("bitgo: unspents target must not be negative, got %d", p.Target)
+	case p.MinSize < 0:
+		return fmt.Errorf("bitgo: unspents min size must not be negative, got %d", p.MinSize)
+	}
+	return nil
+}
+
+// values returns the params encoded as a query string, zero values are omitted.
+func (p *UnspentsParams) values() url.Values {
+	v := url.Values{}
+	if p.Target > 0 {
+		v.Set("target", strconv.FormatInt(int64(p.Target), 10))
+	}
+	if p.MinConfirms > 0 {
+		v.Set("minConfirms", strconv.Itoa(p.MinConfirms))
+	}
+	if p.MinSize > 0 {
+		v.Set("minSize", strconv.FormatInt(int64(p.MinSize), 10))
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Skip > 0 {
+		v.Set("skip", strconv.Itoa(p.Skip))
+	}
+	if p.Segwit != nil {
+		v.Set("segwit", strconv.FormatBool(*p.Segwit))
+	}
+	if p.Instant {
+		v.Set("instant", "true")
+	}
+	return v
 }
 
 // Unspents gets a list of unspent transaction outputs (UTXOs) for a wallet.
 // It invokes f for each page of results.
-// You can filter unspents using query parameters as described in the docs
-// https://bitgo.github.io/bitgo-docs/#list-wallet-unspents.
-func (s *walletService) Unspents(ctx context.Context, walletID string, queryParams url.Values, f func(*UnspentList)) error {
+// You can filter unspents using params, nil params means API defaults are used.
+// The params are not modified when paginating.
+func (s *walletService) Unspents(ctx context.Context, walletID string, params *UnspentsParams, f func(*UnspentList)) error {
+	var list *UnspentList
+	p := s.unspentsPager(walletID, params, func(l *UnspentList) {
+		list = l
+	})
+	for p.Next(ctx) {
+		f(list)
+	}
+	return p.Err()
+}
+
+// UnspentsPager returns a Pager to iterate over unspent transaction outputs (UTXOs) of a wallet.
+// You can filter unspents using params, nil params means API defaults are used.
+// Pagination starts from params.Skip.
+func (s *walletService) UnspentsPager(walletID string, params *UnspentsParams) *Pager[Unspent] {
+	return s.unspentsPager(walletID, params, nil)
+}
+
+// unspentsPager returns a Pager of unspents which passes every fetched list to onList if it's not nil.
+func (s *walletService) unspentsPager(walletID string, params *UnspentsParams, onList func(*UnspentList)) *Pager[Unspent] {
 	path := fmt.Sprintf("wallet/%s/unspents", walletID)
-	skip, err := strconv.Atoi(queryParams.Get("skip"))
-	if err != nil {
-		skip = 0
+	var p UnspentsParams
+	if params != nil {
+		p = *params
 	}
 
-	for {
-		req, err := s.client.NewRequest(ctx, http.MethodGet, path, queryParams, nil)
+	return NewPager(p.Skip, func(ctx context.Context, skip int) (*Page[Unspent], error) {
+		q := p
+		q.Skip = skip
+		if err := q.Validate(); err != nil {
+			return nil, err
+		}
+		req, err := s.client.NewRequest(ctx, http.MethodGet, path, q.values(), nil)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		v := UnspentList{}
-		_, err = s.client.Do(req, &v)
-		if err != nil {
-			return err
+		if _, err = s.client.Do(req, &v); err != nil {
+			return nil, err
 		}
-		f(&v)
-
-		skip = skip + v.Count
-		stopPagination := skip >= v.Total
-		if stopPagination {
-			break
+		if onList != nil {
+			onList(&v)
 		}
-		queryParams.Set("skip", strconv.Itoa(skip))
-	}
-
-	return nil
+		return &Page[Unspent]{ListMeta: v.ListMeta, Items: v.Unspents}, nil
+	})
 }
 
 // TxInfo is a response we get from consolidateunspents API endpoint.
@@ -111,7 +274,60 @@ type TxInfo struct {
 	// Status if the transaction was accepted.
 	Status string `json:"status"`
 	// Transaction fee in satoshis.
-	Fee int64 `json:"fee"`
+	Fee Amount `json:"fee"`
+	// Whether the transaction is a BitGo Instant transaction guaranteed against double spends.
+	Instant bool `json:"instant"`
+	// Travel rule information sent along with the transaction.
+	TravelInfos []TravelInfo `json:"travelInfos"`
+	// Raw is the transaction JSON object as returned by the API.
+	// It gives access to fields which are not modelled yet.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the transaction info and keeps its raw JSON.
+func (t *TxInfo) UnmarshalJSON(b []byte) error {
+	type txInfo TxInfo
+	if err := json.Unmarshal(b, (*txInfo)(t)); err != nil {
+		return err
+	}
+	t.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// TravelInfo is travel rule information about the sender and the recipient of a transaction output.
+// For more details, see https://bitgo.github.io/bitgo-docs/#travel-rule.
+type TravelInfo struct {
+	// The recipient address of the output.
+	ToAddress string `json:"toAddress"`
+	// The amount of the output in satoshis.
+	Amount Amount `json:"amount"`
+	// The name of the sender.
+	FromUserName string `json:"fromUserName"`
+	// The account ID of the sender at the sending institution.
+	FromUserAccount string `json:"fromUserAccount"`
+	// The physical address of the sender.
+	FromUserAddress string `json:"fromUserAddress"`
+	// The name of the recipient.
+	ToUserName string `json:"toUserName"`
+	// The account ID of the recipient at the receiving institution.
+	ToUserAccount string `json:"toUserAccount"`
+	// The physical address of the recipient.
+	ToUserAddress string `json:"toUserAddress"`
+	// Any additional information.
+	Extra json.RawMessage `json:"extra,omitempty"`
+	// Raw is the travel info JSON object as returned by the API.
+	// It gives access to fields which are not modelled yet.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the travel info and keeps its raw JSON.
+func (t *TravelInfo) UnmarshalJSON(b []byte) error {
+	type travelInfo TravelInfo
+	if err := json.Unmarshal(b, (*travelInfo)(t)); err != nil {
+		return err
+	}
+	t.Raw = append(json.RawMessage(nil), b...)
+	return nil
 }
 
 // WalletConsolidateParams represents API parameters used when coalescing UTXOs.
@@ -126,13 +342,13 @@ type WalletConsolidateParams struct {
 	// Passphrase to decrypt the wallet's private key.
 	WalletPassphrase string `json:"walletPassphrase,omitempty"`
 	// Ignore unspents smaller than this amount of satoshis.
-	MinValue int64 `json:"minSize,omitempty"`
+	MinValue Amount `json:"minSize,omitempty"`
 	// Ignore unspents larger than this amount of satoshis.
-	MaxValue int64 `json:"maxSize,omitempty"`
+	MaxValue Amount `json:"maxSize,omitempty"`
 	// Maximum number of consolidation iterations to perform. Must be greater than or equal to 1.
 	MaxIter int `json:"maxIterationCount,omitempty"`
 	// The desired fee rate for the transaction in satoshis/kilobyte.
-	FeeRate int `json:"feeRate,omitempty"`
+	FeeRate FeeRate `json:"feeRate,omitempty"`
 }
 
 // Consolidate coalesces UTXOs currently held in a wallet to a smaller number.
@@ -147,3 +363,140 @@ func (s *walletService) Consolidate(ctx context.Context, walletID string, bodyPa
 	_, err = s.client.Do(req, &tt)
 	return tt, err
 }
+
+// Wallet is a multi-signature wallet.
+// For more details, see https://bitgo.github.io/bitgo-docs/#get-wallet.
+type Wallet struct {
+	// The ID of the wallet which is also its first receive address.
+	ID string `json:"id"`
+	// A human-readable name of the wallet.
+	Label string `json:"label"`
+	// Whether the wallet is active.
+	IsActive bool `json:"isActive"`
+	// The type of the wallet, e.g., "safehd".
+	Type string `json:"type"`
+	// Comma-separated permissions of the user on the wallet, e.g., "admin,spend,view".
+	Permissions string `json:"permissions"`
+	// Number of wallet admins.
+	AdminCount int `json:"adminCount"`
+	// The balance of the wallet in satoshis including unconfirmed transactions.
+	Balance Amount `json:"balance"`
+	// The balance of the wallet in satoshis including only confirmed transactions.
+	ConfirmedBalance Amount `json:"confirmedBalance"`
+	// The balance in satoshis which can be spent, i.e., confirmed and unconfirmed change.
+	SpendableBalance Amount `json:"spendableBalance"`
+	// The balance in satoshis which can be spent with BitGo Instant transactions.
+	InstantBalance Amount `json:"instantBalance"`
+	// Administrative information such as the wallet policy.
+	Admin WalletAdmin `json:"admin"`
+	// Wallet actions waiting for approval.
+	PendingApprovals []PendingApproval `json:"pendingApprovals"`
+}
+
+// WalletAdmin is administrative information of a wallet.
+type WalletAdmin struct {
+	// The policy which controls spending from the wallet. It's nil if the wallet has no policy.
+	Policy *WalletPolicy `json:"policy"`
+}
+
+// WalletPolicy is a set of rules which restrict spending from a wallet.
+// For more details, see https://bitgo.github.io/bitgo-docs/#wallet-policy.
+type WalletPolicy struct {
+	// The ID of the policy.
+	ID string `json:"id"`
+	// The version of the policy which is incremented on every change.
+	Version int `json:"version"`
+	// The time the policy was last changed.
+	Date time.Time `json:"date"`
+	// The rules of the policy.
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule is a wallet policy rule, e.g., a daily spending limit.
+type PolicyRule struct {
+	// The ID of the rule.
+	ID string `json:"id"`
+	// The type of the rule, e.g., "velocityLimit", "bitcoinAddressWhitelist" or "webhook".
+	Type string `json:"type"`
+	// The action taken when the rule is triggered.
+	Action PolicyAction `json:"action"`
+	// The condition of the rule which depends on its type.
+	Condition json.RawMessage `json:"condition"`
+}
+
+// PolicyAction is an action taken when a policy rule is triggered.
+type PolicyAction struct {
+	// The type of the action, e.g., "deny" or "getApproval".
+	Type string `json:"type"`
+}
+
+// WalletList is a list of wallets as retrieved from a list endpoint.
+type WalletList struct {
+	ListMeta
+	Wallets []Wallet `json:"wallets"`
+}
+
+// WalletListParams represents query parameters used when listing wallets.
+// For more details, see https://bitgo.github.io/bitgo-docs/#list-wallets.
+type WalletListParams struct {
+	// Max number of results to return in a single call.
+	Limit int
+	// The starting index number to list from (defaults to 0).
+	Skip int
+	// Whether to include balances of the wallets.
+	GetBalances bool
+}
+
+// values returns the params encoded as a query string, zero values are omitted.
+func (p *WalletListParams) values() url.Values {
+	v := url.Values{}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Skip > 0 {
+		v.Set("skip", strconv.Itoa(p.Skip))
+	}
+	if p.GetBalances {
+		v.Set("getbalances", "true")
+	}
+	return v
+}
+
+// List returns a Pager to iterate over wallets of the user.
+// Nil params means API defaults are used. Pagination starts from params.Skip.
+func (s *walletService) List(params *WalletListParams) *Pager[Wallet] {
+	var p WalletListParams
+	if params != nil {
+		p = *params
+	}
+
+	return NewPager(p.Skip, func(ctx context.Context, skip int) (*Page[Wallet], error) {
+		q := p
+		q.Skip = skip
+		req, err := s.client.NewRequest(ctx, http.MethodGet, "wallet", q.values(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		v := WalletList{}
+		if _, err = s.client.Do(req, &v); err != nil {
+			return nil, err
+		}
+		return &Page[Wallet]{ListMeta: v.ListMeta, Items: v.Wallets}, nil
+	})
+}
+
+// Get gets a wallet by its ID.
+func (s *walletService) Get(ctx context.Context, walletID string) (*Wallet, error) {
+	path := fmt.Sprintf("wallet/%s", walletID)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var w Wallet
+	if _, err = s.client.Do(req, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
@@ -2,11 +2,17 @@ package bitgo_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/marselester/bitgo-v1"
 )
@@ -28,6 +34,13 @@ func TestUnspents(t *testing.T) {
 		Confirmations: 3474,
 		IsChange:      true,
 		Instant:       false,
+		BlockHeight:   570611,
+		Date:          time.Date(2015, 9, 18, 21, 58, 11, 620000000, time.UTC),
+		Wallet:        "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr",
+		ReplayProtection: []string{
+			"b2x",
+			"btg",
+		},
 	}
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -39,8 +52,19 @@ func TestUnspents(t *testing.T) {
 		bitgo.WithBaseURL(srv.URL),
 	)
 	err = client.Wallet.Unspents(context.Background(), "", nil, func(list *bitgo.UnspentList) {
+		if list.PendingTransactions {
+			t.Errorf("expected no pending transactions")
+		}
 		got := list.Unspents[0]
-		if got != want {
+		if len(got.Raw) == 0 {
+			t.Errorf("raw JSON must be kept")
+		}
+		got.Raw = nil
+		if !got.Date.Equal(want.Date) {
+			t.Fatalf("should be %s, not %s", want.Date, got.Date)
+		}
+		got.Date = want.Date
+		if !reflect.DeepEqual(got, want) {
 			t.Fatalf("should be %#v, not %#v", want, got)
 		}
 	})
@@ -49,26 +73,179 @@ func TestUnspents(t *testing.T) {
 	}
 }
 
-func BenchmarkUnspents(b *testing.B) {
-	filename := filepath.Join("testdata", "unspents.json")
-	content, err := ioutil.ReadFile(filename)
+func TestUnspentsPagination(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		skip := r.URL.Query().Get("skip")
+		if skip == "" {
+			skip = "0"
+		}
+		fmt.Fprintf(w, `{"count":1,"start":%s,"total":3,"unspents":[{"value":1}]}`, skip)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	segwit := false
+	params := bitgo.UnspentsParams{
+		MinConfirms: 1,
+		MinSize:     100,
+		Limit:       1,
+		Skip:        1,
+		Segwit:      &segwit,
+	}
+	want := params
+	var pages int
+	err := client.Wallet.Unspents(context.Background(), "", &params, func(list *bitgo.UnspentList) {
+		pages++
+	})
 	if err != nil {
-		b.Fatal(err)
+		t.Fatal(err)
+	}
+	if pages != 2 {
+		t.Errorf("expected 2 pages, got %d", pages)
+	}
+	if params != want {
+		t.Errorf("params must not be modified, got %#v", params)
+	}
+	wantQueries := []string{
+		"limit=1&minConfirms=1&minSize=100&segwit=false&skip=1",
+		"limit=1&minConfirms=1&minSize=100&segwit=false&skip=2",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("expected queries %q, got %q", wantQueries, queries)
 	}
 
+	// Nil params must not panic when there is more than one page.
+	pages = 0
+	err = client.Wallet.Unspents(context.Background(), "", nil, func(list *bitgo.UnspentList) {
+		pages++
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 pages, got %d", pages)
+	}
+}
+
+func TestUnspentsPagerFetchConcurrently(t *testing.T) {
+	const total = 20
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Write(content)
+		var skip, limit int
+		fmt.Sscan(r.URL.Query().Get("skip"), &skip)
+		fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+		var unspents []bitgo.Unspent
+		for i := skip; i < skip+limit && i < total; i++ {
+			unspents = append(unspents, bitgo.Unspent{TxHash: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(bitgo.UnspentList{
+			ListMeta: bitgo.ListMeta{Count: len(unspents), Start: skip, Total: total},
+			Unspents: unspents,
+		})
 	}))
 	defer srv.Close()
 
 	client := bitgo.NewClient(
 		bitgo.WithBaseURL(srv.URL),
 	)
-	f := func(list *bitgo.UnspentList) {}
+	p := client.Wallet.UnspentsPager("", &bitgo.UnspentsParams{Limit: 2})
+	seen := make(map[string]int)
+	err := p.FetchConcurrently(context.Background(), bitgo.FetchOptions[bitgo.Unspent]{Workers: 8}, func(page *bitgo.Page[bitgo.Unspent]) error {
+		for _, u := range page.Items {
+			seen[u.TxHash]++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < total; i++ {
+		if n := seen[strconv.Itoa(i)]; n != 1 {
+			t.Errorf("unspent %d must be fetched once, got %d", i, n)
+		}
+	}
+}
+
+func TestUnspentsParamsValidate(t *testing.T) {
+	tests := []struct {
+		params bitgo.UnspentsParams
+		valid  bool
+	}{
+		{bitgo.UnspentsParams{}, true},
+		{bitgo.UnspentsParams{Limit: 250}, true},
+		{bitgo.UnspentsParams{Limit: 251}, false},
+		{bitgo.UnspentsParams{Limit: -1}, false},
+		{bitgo.UnspentsParams{Skip: -1}, false},
+		{bitgo.UnspentsParams{MinSize: -1}, false},
+	}
+	for _, test := range tests {
+		err := test.params.Validate()
+		if (err == nil) != test.valid {
+			t.Errorf("Validate(%#v) = %v, want valid %v", test.params, err, test.valid)
+		}
+	}
+
+	err := (&bitgo.UnspentsParams{Limit: -1}).Validate()
+	want := "bitgo: unspents limit must be between 1 and 250, or 0 for API default, got -1"
+	if err == nil || err.Error() != want {
+		t.Errorf("expected %q, got %v", want, err)
+	}
+}
+
+func BenchmarkUnspents(b *testing.B) {
+	filename := filepath.Join("testdata", "unspents.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		b.Fatal(err)
+	}
+	// A full page of 250 unspents is made from the first unspent in the fixture.
+	var page struct {
+		Unspents []json.RawMessage `json:"unspents"`
+	}
+	if err = json.Unmarshal(content, &page); err != nil {
+		b.Fatal(err)
+	}
+	unspents := make([]json.RawMessage, 250)
+	for i := range unspents {
+		unspents[i] = page.Unspents[0]
+	}
+	fullPage, err := json.Marshal(map[string]interface{}{
+		"count":    250,
+		"start":    0,
+		"total":    250,
+		"unspents": unspents,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		client.Wallet.Unspents(context.Background(), "", nil, f)
+	for _, bb := range []struct {
+		name    string
+		content []byte
+	}{
+		{"fixture", content},
+		{"limit=250", fullPage},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(bb.content)
+			}))
+			defer srv.Close()
+
+			client := bitgo.NewClient(
+				bitgo.WithBaseURL(srv.URL),
+			)
+			f := func(list *bitgo.UnspentList) {}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				client.Wallet.Unspents(context.Background(), "", nil, f)
+			}
+		})
 	}
 }
 
@@ -79,10 +256,11 @@ func TestConsolidate(t *testing.T) {
 		t.Fatal(err)
 	}
 	want := bitgo.TxInfo{
-		TxID:   "5885a7e6c7802206f69655ed763d14f101cf46501aef38e275c67c72cfcedb75",
-		Tx:     "010000001945f506cad0d2b8be8e36ed5d4dbb7502cd3a2822d01f7afe411f9773c6fd381cad0 ...",
-		Status: "accepted",
-		Fee:    1488,
+		TxID:        "5885a7e6c7802206f69655ed763d14f101cf46501aef38e275c67c72cfcedb75",
+		Tx:          "010000001945f506cad0d2b8be8e36ed5d4dbb7502cd3a2822d01f7afe411f9773c6fd381cad0 ...",
+		Status:      "accepted",
+		Fee:         1488,
+		TravelInfos: []bitgo.TravelInfo{},
 	}
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -97,7 +275,182 @@ func TestConsolidate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if tt[0] != want {
-		t.Fatalf("should be %#v, not %#v", want, tt[0])
+	if len(tt) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(tt))
+	}
+	got := tt[0]
+	got.Raw = nil
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("should be %#v, not %#v", want, got)
+	}
+	if !tt[1].Instant || len(tt[1].TravelInfos) != 1 || tt[1].TravelInfos[0].Amount != 1808804990 {
+		t.Fatalf("unexpected instant transaction %#v", tt[1])
+	}
+}
+
+// assertRoundTrip checks that every field of raw JSON objects is modelled by v's type
+// and survives encoding and decoding.
+func assertRoundTrip(t *testing.T, raw []byte, v interface{}) {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got interface{}
+	if err = json.Unmarshal(raw, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if wantKeys, gotKeys := jsonKeys(want, ""), jsonKeys(got, ""); !reflect.DeepEqual(wantKeys, gotKeys) {
+		t.Errorf("fields should be %v, not %v", wantKeys, gotKeys)
+	}
+
+	again := reflect.New(reflect.TypeOf(v).Elem())
+	if err = json.Unmarshal(b, again.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	b2, err := json.Marshal(again.Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != string(b2) {
+		t.Errorf("round trip should be %s, not %s", b, b2)
+	}
+}
+
+// jsonKeys returns sorted paths of all keys of JSON objects found in v.
+func jsonKeys(v interface{}, prefix string) []string {
+	var keys []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			keys = append(keys, prefix+k)
+			keys = append(keys, jsonKeys(val, prefix+k+".")...)
+		}
+	case []interface{}:
+		for _, val := range v {
+			keys = append(keys, jsonKeys(val, prefix+"[].")...)
+		}
+	}
+	sort.Strings(keys)
+	return compact(keys)
+}
+
+func compact(keys []string) []string {
+	var out []string
+	for i, k := range keys {
+		if i == 0 || k != keys[i-1] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func TestUnspentListRoundTrip(t *testing.T) {
+	content, err := ioutil.ReadFile(filepath.Join("testdata", "unspents.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list bitgo.UnspentList
+	if err = json.Unmarshal(content, &list); err != nil {
+		t.Fatal(err)
+	}
+	assertRoundTrip(t, content, &list)
+}
+
+func TestTxInfoRoundTrip(t *testing.T) {
+	content, err := ioutil.ReadFile(filepath.Join("testdata", "consolidateunspents.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tt []bitgo.TxInfo
+	if err = json.Unmarshal(content, &tt); err != nil {
+		t.Fatal(err)
+	}
+	assertRoundTrip(t, content, &tt)
+}
+
+func TestWalletGet(t *testing.T) {
+	filename := filepath.Join("testdata", "wallet.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	got, err := client.Wallet.Get(context.Background(), "2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/api/v1/wallet/2N91XzUxLrSkfDMaRcwQhe9DauhZMhUoxGr"; path != want {
+		t.Errorf("expected %s path, got %s", want, path)
+	}
+	if got.Label != "Treasury" || !got.IsActive || got.AdminCount != 2 || got.Permissions != "admin,spend,view" {
+		t.Errorf("unexpected wallet %#v", got)
+	}
+	if got.Balance != 78283186932 || got.ConfirmedBalance != 78273186932 || got.SpendableBalance != 78283186932 {
+		t.Errorf("unexpected balances %s, %s, %s", got.Balance, got.ConfirmedBalance, got.SpendableBalance)
+	}
+
+	policy := got.Admin.Policy
+	if policy == nil || policy.Version != 3 || len(policy.Rules) != 1 {
+		t.Fatalf("unexpected policy %#v", policy)
+	}
+	if r := policy.Rules[0]; r.Type != "velocityLimit" || r.Action.Type != "getApproval" || len(r.Condition) == 0 {
+		t.Errorf("unexpected policy rule %#v", r)
+	}
+
+	if len(got.PendingApprovals) != 1 {
+		t.Fatalf("expected 1 pending approval, got %d", len(got.PendingApprovals))
+	}
+	a := got.PendingApprovals[0]
+	if a.ID != "55e8a1a5df8380e0e30e20c7" || a.State != "pending" || a.WalletID != got.ID {
+		t.Errorf("unexpected pending approval %#v", a)
+	}
+	if want := time.Date(2015, 9, 3, 19, 55, 12, 11000000, time.UTC); !a.CreateDate.Equal(want) {
+		t.Errorf("expected %s, got %s", want, a.CreateDate)
+	}
+}
+
+func TestWalletList(t *testing.T) {
+	filename := filepath.Join("testdata", "wallets.json")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	client := bitgo.NewClient(
+		bitgo.WithBaseURL(srv.URL),
+	)
+	var labels []string
+	p := client.Wallet.List(&bitgo.WalletListParams{Limit: 2, GetBalances: true})
+	for wlt, err := range p.All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		labels = append(labels, wlt.Label)
+	}
+	if want := []string{"Treasury", "Hot Wallet"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("expected %q, got %q", want, labels)
+	}
+	if want := "getbalances=true&limit=2"; query != want {
+		t.Errorf("expected %q query, got %q", want, query)
 	}
 }